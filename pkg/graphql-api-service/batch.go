@@ -0,0 +1,251 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultBatchSize = 20
+
+	batchAliasPrefix    = "q"
+	batchVariablePrefix = "titleSlug"
+	questionField       = "question("
+)
+
+type (
+	// ProblemResult is the outcome of fetching a single problem of a batch.
+	ProblemResult struct {
+		TitleSlug string
+		Problem   Problem
+		Err       error
+	}
+
+	// batchResponse keeps questions raw, each of them decodes as problemByTitleSlugQuery question.
+	batchResponse struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []GraphQLError             `json:"errors"`
+	}
+)
+
+// GetProblemsByTitleSlugs fetches problems with as few requests as possible: cached problems are returned
+// right away, the rest are requested in batches of aliased questions, see WithBatchSize.
+// Results follow the order of titleSlugs, failures are reported per problem in ProblemResult.Err,
+// a failed request fails all problems of its batch.
+func (c *Client) GetProblemsByTitleSlugs(ctx context.Context, titleSlugs []string) []ProblemResult {
+	results := make([]ProblemResult, len(titleSlugs))
+	if c.offline {
+		for i, titleSlug := range titleSlugs {
+			results[i].TitleSlug = titleSlug
+			results[i].Problem, results[i].Err = c.offlineProblem(titleSlug)
+		}
+		return results
+	}
+
+	positions := make(map[string][]int, len(titleSlugs))
+	missing := make([]string, 0, len(titleSlugs))
+	for i, titleSlug := range titleSlugs {
+		results[i].TitleSlug = titleSlug
+		if p, cacheHit := c.problemCache.Get(titleSlug); cacheHit {
+			results[i].Problem = p
+			continue
+		}
+		if _, ok := positions[titleSlug]; !ok {
+			missing = append(missing, titleSlug)
+		}
+		positions[titleSlug] = append(positions[titleSlug], i)
+	}
+
+	for start := 0; start < len(missing); start += c.batchSize {
+		end := start + c.batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		for _, r := range c.getProblemBatch(ctx, missing[start:end]) {
+			for _, i := range positions[r.TitleSlug] {
+				results[i] = r
+			}
+		}
+	}
+
+	return results
+}
+
+// getProblemBatch requests problems in a single request, results follow the order of titleSlugs.
+func (c *Client) getProblemBatch(ctx context.Context, titleSlugs []string) []ProblemResult {
+	results := make([]ProblemResult, len(titleSlugs))
+	for i, titleSlug := range titleSlugs {
+		results[i].TitleSlug = titleSlug
+	}
+	fail := func(err error) []ProblemResult {
+		for i := range results {
+			results[i].Err = wrapAPIError(err, "get problem data batch from API")
+		}
+		return results
+	}
+
+	query, err := batchQuery(c.site.problemByTitleSlugQuery, len(titleSlugs))
+	if err != nil {
+		return fail(fmt.Errorf("build query: %w", err))
+	}
+	variables := make(map[string]interface{}, len(titleSlugs))
+	for i, titleSlug := range titleSlugs {
+		variables[fmt.Sprintf("%s%d", batchVariablePrefix, i)] = titleSlug
+	}
+
+	req, err := c.newRequest(ctx, query, variables)
+	if err != nil {
+		return fail(fmt.Errorf("init request: %w", err))
+	}
+	c.addRefererHeader(req, c.baseURL+c.site.problemListReferer)
+
+	body, err := c.do(req, decodeGraphQLPartialResponse, true)
+	if err != nil {
+		return fail(fmt.Errorf("query: %w", err))
+	}
+
+	parsedResponse := &batchResponse{}
+	if err = json.Unmarshal(body, parsedResponse); err != nil {
+		return fail(fmt.Errorf("response unmarshal: %w", err))
+	}
+
+	itemErrors := make(map[string]*GraphQLError, len(parsedResponse.Errors))
+	for i := range parsedResponse.Errors {
+		gqlErr := &parsedResponse.Errors[i]
+		alias := ""
+		if len(gqlErr.Path) > 0 {
+			alias, _ = gqlErr.Path[0].(string)
+		}
+		if _, ok := parsedResponse.Data[alias]; !ok {
+			// not tied to a single question
+			return fail(gqlErr)
+		}
+		itemErrors[alias] = gqlErr
+	}
+
+	for i, titleSlug := range titleSlugs {
+		alias := fmt.Sprintf("%s%d", batchAliasPrefix, i)
+		data, err := decodeBatchQuestion(parsedResponse.Data[alias])
+		if err != nil {
+			results[i].Err = fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+			continue
+		}
+		results[i].Problem, results[i].Err = c.batchProblem(titleSlug, data, itemErrors[alias])
+	}
+	return results
+}
+
+// decodeBatchQuestion decodes an aliased question of the batch, nil for missing and null ones.
+func decodeBatchQuestion(raw json.RawMessage) (*problemData, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	d := &problemByTitleSlugQueryData{}
+	if err := json.Unmarshal(raw, &d.QuestionData); err != nil {
+		return nil, err
+	}
+	translation := translatedQuestion{}
+	if d.QuestionData != nil {
+		if err := json.Unmarshal(raw, &translation); err != nil {
+			return nil, err
+		}
+	}
+	return d.problemData(translation), nil
+}
+
+func (c *Client) batchProblem(titleSlug string, data *problemData, gqlErr *GraphQLError) (Problem, error) {
+	if gqlErr != nil {
+		return Problem{}, wrapAPIError(gqlErr, "get problem data from API")
+	}
+	if data == nil {
+		return Problem{}, wrapAPIError(fmt.Errorf("question %s: %w", titleSlug, ErrorProblemNotFound), "get problem data from API")
+	}
+	if err := c.parseAdditionalData(data); err != nil {
+		return Problem{}, fmt.Errorf("%w: parse fields: %v", ErrorSystem, err)
+	}
+
+	problem, err := externalProblemFromProblemData(data)
+	if err != nil {
+		return Problem{}, fmt.Errorf("%w: %v", ErrorSystem, err)
+	}
+	c.problemCache.Add(&problem)
+
+	return problem, nil
+}
+
+// batchQuery repeats the question selection of the single problem query n times under q0..qN aliases,
+// so that both queries always request the same fields.
+func batchQuery(problemQuery string, n int) (string, error) {
+	selection, err := questionSelection(problemQuery)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("query questionDataBatch(")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "$%s%d: String!", batchVariablePrefix, i)
+	}
+	b.WriteString(") {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\t%s%d: question(titleSlug: $%s%d) %s\n", batchAliasPrefix, i, batchVariablePrefix, i, selection)
+	}
+	b.WriteString("}\n")
+
+	return b.String(), nil
+}
+
+// questionSelection returns the selection set of the question field including braces.
+func questionSelection(query string) (string, error) {
+	start := strings.Index(query, questionField)
+	if start < 0 {
+		return "", fmt.Errorf("no question field in query")
+	}
+	open := strings.IndexByte(query[start:], '{')
+	if open < 0 {
+		return "", fmt.Errorf("no question selection in query")
+	}
+	open += start
+
+	depth := 0
+	for i := open; i < len(query); i++ {
+		switch query[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return query[open : i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unterminated question selection in query")
+}
+
+// decodeGraphQLPartialResponse keeps the whole response as long as it has data,
+// errors of single fields are reported by the caller.
+func decodeGraphQLPartialResponse(response *http.Response, body []byte) ([]byte, error) {
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return decodeGraphQLResponse(response, body)
+	}
+
+	dataField := &responseDataWrapper{}
+	if err := json.Unmarshal(body, dataField); err != nil {
+		return nil, fmt.Errorf("response unmarshal: %w", err)
+	}
+	if len(dataField.Data) == 0 || string(dataField.Data) == "null" {
+		if len(dataField.Errors) > 0 {
+			return nil, &dataField.Errors[0]
+		}
+		return nil, fmt.Errorf("no data in response")
+	}
+
+	return body, nil
+}
@@ -0,0 +1,116 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/gqlgen"
+)
+
+func TestUnit_BatchQuery(t *testing.T) {
+	query, err := batchQuery(problemByTitleSlugQuery, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, query, "query questionDataBatch($titleSlug0: String!, $titleSlug1: String!, $titleSlug2: String!) {")
+	assert.Contains(t, query, "q2: question(titleSlug: $titleSlug2) {")
+
+	schema, err := gqlgen.LoadSchemaFile("graphql/schema.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	op, err := gqlgen.ParseOperation(query)
+	if assert.NoError(t, err) {
+		assert.NoError(t, schema.Validate(op))
+	}
+
+	for site, config := range sites {
+		_, err = batchQuery(config.problemByTitleSlugQuery, 1)
+		assert.NoError(t, err, site)
+	}
+	_, err = batchQuery(userStatusQuery, 1)
+	assert.Error(t, err)
+}
+
+func TestUnit_GetProblemsByTitleSlugs(t *testing.T) {
+	var batches [][]string
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		graphqlAPIEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			q := graphQLRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+			assert.True(t, strings.HasPrefix(q.Query, "query questionDataBatch("))
+
+			var slugs []string
+			data := make([]string, 0, len(q.Variables))
+			var errs []string
+			for i := 0; i < len(q.Variables); i++ {
+				slug := q.Variables[fmt.Sprintf("titleSlug%d", i)].(string)
+				slugs = append(slugs, slug)
+
+				switch slug {
+				case "missing":
+					data = append(data, fmt.Sprintf(`"q%d":null`, i))
+				case "broken":
+					data = append(data, fmt.Sprintf(`"q%d":null`, i))
+					errs = append(errs, fmt.Sprintf(`{"message":"internal error","path":["q%d"]}`, i))
+				default:
+					data = append(data, fmt.Sprintf(`"q%d":{"questionId":"%d","title":"%s","titleSlug":"%s"}`, i, len(slug), slug, slug))
+				}
+			}
+			batches = append(batches, slugs)
+
+			body := `{"data":{` + strings.Join(data, ",") + `}`
+			if len(errs) > 0 {
+				body += `,"errors":[` + strings.Join(errs, ",") + `]`
+			}
+			writeJSON(t, w, body+"}")
+		},
+	})
+	c.batchSize = 2
+	c.problemCache.Add(&Problem{ID: 100, TitleSlug: "cached"})
+
+	results := c.GetProblemsByTitleSlugs(context.Background(), []string{"two-sum", "cached", "missing", "broken", "two-sum", "3sum"})
+
+	assert.Equal(t, [][]string{{"two-sum", "missing"}, {"broken", "3sum"}}, batches)
+	if !assert.Len(t, results, 6) {
+		return
+	}
+	for i, slug := range []string{"two-sum", "cached", "missing", "broken", "two-sum", "3sum"} {
+		assert.Equal(t, slug, results[i].TitleSlug)
+	}
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 7, results[0].Problem.ID)
+	assert.Equal(t, 100, results[1].Problem.ID)
+	assert.ErrorIs(t, results[2].Err, ErrorProblemNotFound)
+	assert.ErrorIs(t, results[3].Err, ErrorSystem)
+	assert.Equal(t, results[0], results[4])
+	assert.Equal(t, 4, results[5].Problem.ID)
+
+	// fetched problems are cached
+	p, cacheHit := c.problemCache.Get("3sum")
+	assert.True(t, cacheHit)
+	assert.Equal(t, results[5].Problem, p)
+}
+
+func TestUnit_GetProblemsByTitleSlugsRequestError(t *testing.T) {
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		graphqlAPIEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(t, w, `{"errors":[{"message":"Variable titleSlug0 got invalid value"}]}`)
+		},
+	})
+
+	results := c.GetProblemsByTitleSlugs(context.Background(), []string{"a", "b"})
+	if assert.Len(t, results, 2) {
+		assert.ErrorIs(t, results[0].Err, ErrorSystem)
+		assert.ErrorIs(t, results[1].Err, ErrorSystem)
+	}
+	assert.Empty(t, c.GetProblemsByTitleSlugs(context.Background(), nil))
+}
@@ -1,78 +1,437 @@
 package graphqlapiservice
 
 import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 )
 
 const (
-	problemExpiration = 1 * time.Hour
-	cacheTick         = 1 * time.Minute
+	defaultCacheTTL        = 1 * time.Hour
+	defaultCacheMaxEntries = 2000
+	defaultCacheMaxBytes   = 32 << 20
+	defaultCacheStaleTTL   = 24 * time.Hour
+	cacheTick              = 1 * time.Minute
 )
 
 type (
-	cache struct {
-		sync.RWMutex
-		close    chan struct{}
-		problems map[string]entry
+	// Cache stores fetched problems by title slug, implementations must be safe for concurrent use.
+	Cache interface {
+		Get(titleSlug string) (Problem, bool)
+		Add(p *Problem)
 	}
 
-	entry struct {
+	// cacheRunner is implemented by caches that need background maintenance while the client runs.
+	cacheRunner interface {
+		run(ctx context.Context)
+	}
+
+	// staleCache is implemented by caches that keep expired problems, which are served while
+	// they are fetched again in the background, see Client.GetProblemByTitleSlugContext.
+	staleCache interface {
+		Entry(titleSlug string) (CacheEntry, bool)
+	}
+
+	// frozenCache is implemented by caches that keep imported snapshots as they are, see Client.ImportSnapshot.
+	frozenCache interface {
+		// addFrozen keeps the problem without expiration and fails rather than evicting other entries.
+		addFrozen(p *Problem, fetchedAt time.Time) error
+	}
+
+	// cacheStatsReporter is implemented by caches that count their hits and misses, see Client.Metrics.
+	cacheStatsReporter interface {
+		Stats() CacheStats
+	}
+
+	noCache struct{}
+
+	// CacheConfig limits LRUCache, zero limits disable the corresponding check.
+	CacheConfig struct {
+		MaxEntries int           // number of problems kept
+		MaxBytes   int64         // approximate size of problems kept, measured as their JSON encoding
+		TTL        time.Duration // expiration of entries added with Add
+		StaleTTL   time.Duration // expired entries are kept for, see LRUCache.Entry
+	}
+
+	CacheStats struct {
+		Hits        int64
+		Misses      int64
+		Evictions   int64 // entries dropped to fit the limits
+		Expirations int64 // entries dropped once stale for StaleTTL
+		StaleHits   int64 // expired entries served while being fetched again
+		Entries     int
+		Bytes       int64
+	}
+
+	// LRUCache is a Cache that evicts least recently used problems once the limits are exceeded.
+	LRUCache struct {
+		mu    sync.Mutex
+		cfg   CacheConfig
+		items map[string]*list.Element
+		order *list.List // front is the most recently used
+		stats CacheStats
+		now   func() time.Time
+	}
+
+	// LayeredCache chains caches from the fastest to the slowest one, e.g. LRUCache over DiskCache.
+	// Reads go through the layers until a hit, which is then copied to the layers above it,
+	// writes go to all layers.
+	LayeredCache struct {
+		layers []Cache
+
+		mu     sync.Mutex
+		hits   int64
+		misses int64
+	}
+
+	lruEntry struct {
 		problem Problem
-		expires time.Time
+		size    int64
+		added   time.Time
+		expires time.Time // zero for entries that never expire
 	}
 )
 
-func newCache() cache {
-	return cache{
-		problems: make(map[string]entry),
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxEntries: defaultCacheMaxEntries,
+		MaxBytes:   defaultCacheMaxBytes,
+		TTL:        defaultCacheTTL,
+		StaleTTL:   defaultCacheStaleTTL,
+	}
+}
+
+func NewLRUCache(cfg CacheConfig) *LRUCache {
+	return &LRUCache{
+		cfg:   cfg,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+// Add caches the problem for the configured TTL.
+func (c *LRUCache) Add(p *Problem) {
+	c.AddWithTTL(p, c.cfg.TTL)
+}
+
+// AddWithTTL caches the problem for the given duration, non-positive ttl keeps it until evicted.
+// Problems larger than MaxBytes are not cached.
+func (c *LRUCache) AddWithTTL(p *Problem, ttl time.Duration) {
+	e := &lruEntry{
+		problem: *p,
+		size:    problemSize(p),
+		added:   c.now(),
+	}
+	if ttl > 0 {
+		e.expires = e.added.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[p.TitleSlug]; ok {
+		c.remove(el)
+	}
+	if c.cfg.MaxBytes > 0 && e.size > c.cfg.MaxBytes {
+		return
+	}
+
+	c.items[p.TitleSlug] = c.order.PushFront(e)
+	c.stats.Bytes += e.size
+
+	for c.overLimits() {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *LRUCache) addFrozen(p *Problem, fetchedAt time.Time) error {
+	e := &lruEntry{
+		problem: *p,
+		size:    problemSize(p),
+		added:   fetchedAt,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, bytes := c.order.Len()+1, c.stats.Bytes+e.size
+	if el, ok := c.items[p.TitleSlug]; ok {
+		entries--
+		bytes -= el.Value.(*lruEntry).size
+	}
+	if (c.cfg.MaxEntries > 0 && entries > c.cfg.MaxEntries) || (c.cfg.MaxBytes > 0 && bytes > c.cfg.MaxBytes) {
+		return fmt.Errorf("%w: no room for %s", ErrorCacheFull, p.TitleSlug)
+	}
+
+	if el, ok := c.items[p.TitleSlug]; ok {
+		c.remove(el)
+	}
+	c.items[p.TitleSlug] = c.order.PushFront(e)
+	c.stats.Bytes += e.size
+	return nil
+}
+
+func (c *LRUCache) Get(titleSlug string) (Problem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[titleSlug]
+	if !ok {
+		c.stats.Misses++
+		return Problem{}, false
+	}
+
+	e := el.Value.(*lruEntry)
+	if c.expired(e) {
+		if c.dropped(e) {
+			c.remove(el)
+			c.stats.Expirations++
+		}
+		c.stats.Misses++
+		return Problem{}, false
+	}
+
+	c.order.MoveToFront(el)
+	c.stats.Hits++
+	return e.problem, true
+}
+
+// Entry returns the cached problem even if it has expired, as long as it has been stale for less than StaleTTL.
+func (c *LRUCache) Entry(titleSlug string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[titleSlug]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	e := el.Value.(*lruEntry)
+	if c.dropped(e) {
+		return CacheEntry{}, false
+	}
+
+	c.order.MoveToFront(el)
+	if c.expired(e) {
+		c.stats.StaleHits++
 	}
+	return CacheEntry{Problem: e.problem, FetchedAt: e.added, Expires: e.expires}, true
 }
 
-func (c *cache) run() {
-	go func() {
-		ticker := time.NewTicker(cacheTick)
-		defer ticker.Stop()
+func (c *LRUCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		for {
-			select {
-			case <-ticker.C:
-				c.cleanup()
-			case <-c.close:
-				return
+	stats := c.stats
+	stats.Entries = len(c.items)
+	return stats
+}
+
+// run cleans up expired entries until ctx is done.
+func (c *LRUCache) run(ctx context.Context) {
+	ticker := time.NewTicker(cacheTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanup()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *LRUCache) cleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, el := range c.items {
+		if c.dropped(el.Value.(*lruEntry)) {
+			c.remove(el)
+			c.stats.Expirations++
+		}
+	}
+}
+
+func (c *LRUCache) overLimits() bool {
+	if c.order.Len() == 0 {
+		return false
+	}
+	return (c.cfg.MaxEntries > 0 && c.order.Len() > c.cfg.MaxEntries) ||
+		(c.cfg.MaxBytes > 0 && c.stats.Bytes > c.cfg.MaxBytes)
+}
+
+func (c *LRUCache) expired(e *lruEntry) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+// dropped reports whether the entry has been stale for StaleTTL and is no longer served at all.
+func (c *LRUCache) dropped(e *lruEntry) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires.Add(c.cfg.StaleTTL))
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	e := c.order.Remove(el).(*lruEntry)
+	delete(c.items, e.problem.TitleSlug)
+	c.stats.Bytes -= e.size
+}
+
+// problemSize approximates memory taken by the problem with the length of its JSON encoding,
+// which is dominated by the statement and code snippets.
+func problemSize(p *Problem) int64 {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+func NewLayeredCache(layers ...Cache) *LayeredCache {
+	return &LayeredCache{layers: layers}
+}
+
+func (c *LayeredCache) Get(titleSlug string) (Problem, bool) {
+	for i, layer := range c.layers {
+		p, ok := layer.Get(titleSlug)
+		if !ok {
+			continue
+		}
+		for _, upper := range c.layers[:i] {
+			upper.Add(&p)
+		}
+		c.count(true)
+		return p, true
+	}
+	c.count(false)
+	return Problem{}, false
+}
+
+func (c *LayeredCache) Add(p *Problem) {
+	for _, layer := range c.layers {
+		layer.Add(p)
+	}
+}
+
+// Stats counts hits and misses of the whole chain, the rest comes from the first layer reporting stats.
+func (c *LayeredCache) Stats() CacheStats {
+	stats := CacheStats{}
+	for _, layer := range c.layers {
+		if r, ok := layer.(cacheStatsReporter); ok {
+			stats = r.Stats()
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats.Hits, stats.Misses = c.hits, c.misses
+	return stats
+}
+
+// run maintains all layers that need it until ctx is done.
+func (c *LayeredCache) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, layer := range c.layers {
+		if r, ok := layer.(cacheRunner); ok {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.run(ctx)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// Entry looks for expired problems in the layers keeping them, see LRUCache.Entry and DiskCache.Entry.
+func (c *LayeredCache) Entry(titleSlug string) (CacheEntry, bool) {
+	for _, layer := range c.layers {
+		if s, ok := layer.(staleCache); ok {
+			if e, ok := s.Entry(titleSlug); ok {
+				return e, true
 			}
 		}
-	}()
+	}
+	return CacheEntry{}, false
 }
 
-func (c *cache) cleanup() {
-	c.Lock()
-	defer c.Unlock()
-	for titleSlug := range c.problems {
-		if c.problems[titleSlug].expires.Before(time.Now()) {
-			delete(c.problems, titleSlug)
+// addFrozen succeeds if any of the layers keeps the problem, e.g. DiskCache below a full LRUCache.
+func (c *LayeredCache) addFrozen(p *Problem, fetchedAt time.Time) error {
+	err := fmt.Errorf("%w: no layer keeps snapshots", ErrorCacheFull)
+	kept := false
+	for _, layer := range c.layers {
+		f, ok := layer.(frozenCache)
+		if !ok {
+			layer.Add(p)
+			continue
+		}
+		if lErr := f.addFrozen(p, fetchedAt); lErr != nil {
+			err = lErr
+		} else {
+			kept = true
 		}
 	}
+	if kept {
+		return nil
+	}
+	return err
 }
 
-func (c *cache) stop() {
-	c.close <- struct{}{}
+func (c *LayeredCache) dailyProblem(date string) (string, bool) {
+	if s, ok := c.store(); ok {
+		return s.dailyProblem(date)
+	}
+	return "", false
 }
 
-func (c *cache) add(p *Problem) {
-	c.Lock()
-	defer c.Unlock()
+func (c *LayeredCache) setDailyProblem(date, titleSlug string) error {
+	if s, ok := c.store(); ok {
+		return s.setDailyProblem(date, titleSlug)
+	}
+	return nil
+}
 
-	c.problems[p.TitleSlug] = entry{
-		problem: *p,
-		expires: time.Now().Add(problemExpiration),
+func (c *LayeredCache) storedProblemIndex() ([]problemTitleMap, bool) {
+	if s, ok := c.store(); ok {
+		return s.storedProblemIndex()
 	}
+	return nil, false
 }
 
-func (c *cache) get(titleSlug string) (Problem, bool) {
-	c.RLock()
-	defer c.RUnlock()
+func (c *LayeredCache) setProblemIndex(refs []problemTitleMap) error {
+	if s, ok := c.store(); ok {
+		return s.setProblemIndex(refs)
+	}
+	return nil
+}
 
-	e, ok := c.problems[titleSlug]
-	return e.problem, ok
+// store returns the first layer keeping offline data.
+func (c *LayeredCache) store() (offlineStore, bool) {
+	for _, layer := range c.layers {
+		if s, ok := layer.(offlineStore); ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+func (c *LayeredCache) count(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
 }
+
+func (noCache) Get(string) (Problem, bool) {
+	return Problem{}, false
+}
+
+func (noCache) Add(*Problem) {}
@@ -8,30 +8,192 @@ import (
 )
 
 func TestUnit_Cache(t *testing.T) {
-	c := newCache()
+	cfg := DefaultCacheConfig()
+	cfg.StaleTTL = 0 // drop entries right away
+	c := NewLRUCache(cfg)
+	now := time.Now()
+	c.now = func() time.Time { return now }
 
 	testProblem := Problem{
 		ID:        1,
 		Title:     "Test Problem",
 		TitleSlug: "test-problem",
 	}
-	c.add(&testProblem)
-	p, ok := c.get("test-problem")
+	c.Add(&testProblem)
+	p, ok := c.Get("test-problem")
 	assert.True(t, ok)
 	assert.Equal(t, testProblem, p)
 
-	p, ok = c.get("unknown-problem")
+	p, ok = c.Get("unknown-problem")
 	assert.False(t, ok)
 	assert.Equal(t, Problem{}, p)
 
-	c.problems["123"] = entry{
-		problem: Problem{ID: 2},
-		expires: time.Now(),
-	}
-	_, ok = c.get("123")
+	c.AddWithTTL(&Problem{ID: 2, TitleSlug: "short-lived"}, time.Second)
+	c.AddWithTTL(&Problem{ID: 3, TitleSlug: "forever"}, 0)
+	_, ok = c.Get("short-lived")
 	assert.True(t, ok)
 
+	now = now.Add(time.Second)
+	_, ok = c.Get("short-lived")
+	assert.False(t, ok)
+
+	now = now.Add(defaultCacheTTL)
 	c.cleanup()
-	_, ok = c.get("123")
+	_, ok = c.Get("test-problem")
+	assert.False(t, ok)
+	_, ok = c.Get("forever")
+	assert.True(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(3), stats.Hits)
+	assert.Equal(t, int64(3), stats.Misses)
+	assert.Equal(t, int64(2), stats.Expirations)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, problemSize(&Problem{ID: 3, TitleSlug: "forever"}), stats.Bytes)
+}
+
+func TestUnit_CacheStaleEntries(t *testing.T) {
+	c := NewLRUCache(CacheConfig{TTL: time.Hour, StaleTTL: time.Hour})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Add(&Problem{ID: 1, TitleSlug: "two-sum"})
+	e, ok := c.Entry("two-sum")
+	assert.True(t, ok)
+	assert.False(t, e.Stale(now))
+	assert.Equal(t, now, e.FetchedAt)
+
+	now = now.Add(time.Hour)
+	_, ok = c.Get("two-sum")
+	assert.False(t, ok, "expired entries are misses")
+	e, ok = c.Entry("two-sum")
+	assert.True(t, ok)
+	assert.True(t, e.Stale(now))
+	assert.Equal(t, 1, e.Problem.ID)
+
+	now = now.Add(time.Hour)
+	c.cleanup()
+	_, ok = c.Entry("two-sum")
+	assert.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(1), stats.StaleHits)
+	assert.Equal(t, int64(1), stats.Expirations)
+	assert.Equal(t, 0, stats.Entries)
+}
+
+func TestUnit_CacheLimits(t *testing.T) {
+	problem := func(titleSlug string) *Problem {
+		return &Problem{TitleSlug: titleSlug, Hints: []string{"hint"}}
+	}
+	size := problemSize(problem("a"))
+
+	testCases := map[string]struct {
+		cfg     CacheConfig
+		adds    []string
+		gets    []string // between adds of the first two and the rest
+		kept    []string
+		evicted int64
+	}{
+		"max entries": {
+			cfg:     CacheConfig{MaxEntries: 2},
+			adds:    []string{"a", "b", "c"},
+			kept:    []string{"b", "c"},
+			evicted: 1,
+		},
+		"recently used entries are kept": {
+			cfg:     CacheConfig{MaxEntries: 2},
+			adds:    []string{"a", "b", "c"},
+			gets:    []string{"a"},
+			kept:    []string{"a", "c"},
+			evicted: 1,
+		},
+		"max bytes": {
+			cfg:     CacheConfig{MaxBytes: 2*size + 1},
+			adds:    []string{"a", "b", "c", "d"},
+			kept:    []string{"c", "d"},
+			evicted: 2,
+		},
+		"entry larger than max bytes": {
+			cfg:  CacheConfig{MaxBytes: size - 1},
+			adds: []string{"a"},
+		},
+		"replaced entry": {
+			cfg:  CacheConfig{MaxEntries: 2},
+			adds: []string{"a", "a", "b"},
+			kept: []string{"a", "b"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := NewLRUCache(test.cfg)
+			for i, titleSlug := range test.adds {
+				if i == 2 {
+					for _, get := range test.gets {
+						c.Get(get)
+					}
+				}
+				c.Add(problem(titleSlug))
+			}
+
+			stats := c.Stats()
+			assert.Equal(t, test.evicted, stats.Evictions)
+			assert.Equal(t, len(test.kept), stats.Entries)
+			assert.Equal(t, int64(len(test.kept))*size, stats.Bytes)
+			for _, titleSlug := range test.kept {
+				_, ok := c.Get(titleSlug)
+				assert.True(t, ok, titleSlug)
+			}
+		})
+	}
+}
+
+func TestUnit_CacheMetrics(t *testing.T) {
+	c, err := NewAPIClient(WithRateLimit(0, 0))
+	assert.NoError(t, err)
+	c.problemCache.Add(&Problem{TitleSlug: "two-sum"})
+	c.problemCache.Get("two-sum")
+	c.problemCache.Get("3sum")
+
+	m := c.Metrics()
+	assert.Equal(t, int64(1), m.Cache.Hits)
+	assert.Equal(t, int64(1), m.Cache.Misses)
+	assert.Equal(t, 1, m.Cache.Entries)
+
+	c, err = NewAPIClient(WithRateLimit(0, 0), WithCache(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{}, c.Metrics().Cache)
+}
+
+func TestUnit_LayeredCache(t *testing.T) {
+	memory := NewLRUCache(DefaultCacheConfig())
+	disk, err := NewDiskCache(t.TempDir(), 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	c := NewLayeredCache(memory, disk)
+
+	// write-through
+	c.Add(&Problem{ID: 1, TitleSlug: "two-sum"})
+	_, ok := disk.Get("two-sum")
+	assert.True(t, ok)
+
+	// read-through fills the memory layer
+	assert.NoError(t, disk.Put(&Problem{ID: 2, TitleSlug: "add-two-numbers"}))
+	p, ok := c.Get("add-two-numbers")
+	assert.True(t, ok)
+	assert.Equal(t, 2, p.ID)
+	p, ok = memory.Get("add-two-numbers")
+	assert.True(t, ok)
+	assert.Equal(t, 2, p.ID)
+
+	_, ok = c.Get("3sum")
 	assert.False(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
 }
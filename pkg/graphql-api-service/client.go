@@ -7,79 +7,34 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
 //go:generate mockgen -source=client.go -destination=client_mock_test.go -package=graphql_api_service . httpClient
+//go:generate go run ./internal/cmd/querygen -schema graphql/schema.json -out queries_gen.go graphql
 
 type (
 	httpClient interface {
 		Do(req *http.Request) (*http.Response, error)
 	}
+
+	responseDecoder func(response *http.Response, body []byte) ([]byte, error)
 )
 
 const (
-	leetcodeURL        = "https://leetcode.com"
-	graphqlAPIEndpoint = leetcodeURL + "/graphql"
-
-	// consult reference/questionData-response.json for fields requested by browser
-	problemByTitleSlugQuery = `query questionData($titleSlug: String!) {
-	 questionData: question(titleSlug: $titleSlug) {
-		questionId
-		title
-		titleSlug
-		exampleTestcases
-		codeSnippets {
-			langSlug
-			code
-		}
-		content
-		isPaidOnly
-		canSeeQuestion
-		hints
-		metaData
-	}
-}
-`
-	totalProblemsQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput) {
-	problemsetQuestionList: questionList(
-		categorySlug: $categorySlug
-		filters: $filters
-	) {
-		total: totalNum
-	}
-}
-`
-	problemListQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput, $limit: Int) {
-	problemsetQuestionList: questionList(
-		categorySlug: $categorySlug
-		filters: $filters
-		limit: $limit
-	) {
-		questions: data {
-			title
-			titleSlug
-			frontendQuestionId: questionFrontendId
-		}
-	}
-}
-`
-	dailyProblemQuery = `query questionOfToday {
-	activeDailyCodingChallengeQuestion {
-		question {
-			titleSlug
-		}
-	}
-}
-`
+	defaultBaseURL     = "https://leetcode.com"
+	graphqlAPIEndpoint = "/graphql"
+
 	variableTitleSlug    = "titleSlug"
 	variableCategorySlug = "categorySlug"
 	variableFilters      = "filters"
 	variableLimit        = "limit"
 
 	csrfTokenCookie        = "csrftoken"
-	problemRefererTemplate = leetcodeURL + "/problems/%s/description/"
-	problemListReferer     = leetcodeURL + "/problemset/all/"
+	csrfTokenHeader        = "x-csrftoken"
+	problemRefererTemplate = "/problems/%s/description/"
+	problemListReferer     = "/problemset/all/"
 )
 
 type (
@@ -91,63 +46,72 @@ type (
 	queryVariables map[string]interface{}
 
 	responseDataWrapper struct {
-		Data json.RawMessage `json:"data"`
+		Data   json.RawMessage `json:"data"`
+		Errors []GraphQLError  `json:"errors"`
 	}
 
-	problemDataResponseWrapper struct {
-		Question problemData `json:"questionData"`
-	}
+	// problemData is a question of problemByTitleSlugQuery with the fields the API encodes as JSON strings decoded.
+	problemData struct {
+		ID         string
+		FrontendID string
+		Title      string
+		TitleSlug  string
 
-	totalProblemsData struct {
-		QuestionList struct {
-			TotalNum int `json:"total"`
-		} `json:"problemsetQuestionList"`
-	}
+		Likes     int
+		Dislikes  int
+		ACRate    float64
+		TopicTags []topicTag
 
-	problemReferenceList struct {
-		Questions []problemTitleMap `json:"questions"`
-	}
+		TranslatedTitle   string // leetcode.cn only
+		TranslatedContent string // leetcode.cn only
 
-	problemData struct {
-		ID        string `json:"questionId"`
-		Title     string `json:"title"`
-		TitleSlug string `json:"titleSlug"`
+		ExampleTestcases string
+		CodeSnippets     []codeSnippet
+		Content          string
 
-		// TODO: parse testcases from problem description
-		CodeSnippets []codeSnippet `json:"codeSnippets"`
-		Content      string        `json:"content"`
+		IsPaidOnly     bool
+		CanSeeQuestion bool
+		Difficulty     string
+		CategoryTitle  string
+		Hints          []string
 
-		IsPaidOnly     bool     `json:"isPaidOnly"`
-		CanSeeQuestion bool     `json:"canSeeQuestion"`
-		Difficulty     string   `json:"difficulty"`
-		CategoryTitle  string   `json:"categoryTitle"`
-		Hints          []string `json:"hints"`
+		MetaDataRaw string // data on function name and inputs and outputs
+		StatsRaw    string
+		EnvInfoRaw  string
+		MetaData    metaData
+		Stats       stats
+		EnvInfo     envInfo
 
-		MetaDataRaw string   `json:"metaData"` // data on function name and inputs and outputs
-		StatsRaw    string   `json:"stats"`
-		EnvInfoRaw  string   `json:"envInfo"`
-		MetaData    metaData `json:"-"`
-		Stats       stats    `json:"-"`
-		EnvInfo     envInfo  `json:"-"`
+		SimilarQuestionsRaw string
+		CompanyTagStatsRaw  *string // null unless premium
+		SimilarQuestions    []similarQuestion
+		CompanyTagStats     companyTagStats
 	}
 
-	codeSnippet struct {
-		LangSlug string `json:"langSlug"`
-		Code     string `json:"code"`
+	similarQuestion struct {
+		Title      string `json:"title"`
+		TitleSlug  string `json:"titleSlug"`
+		Difficulty string `json:"difficulty"`
 	}
 
-	problemTitleMap struct {
-		Title     string `json:"title"`
-		TitleSlug string `json:"titleSlug"`
-		ID        string `json:"questionFrontendId"`
+	companyTagStats map[string][]companyTag // time period => tags
+
+	companyTag struct {
+		Name             string `json:"name"`
+		Slug             string `json:"slug"`
+		TimesEncountered int    `json:"timesEncountered"`
 	}
 
-	dailyChallengeResponse struct {
-		Challenge struct {
-			Question struct {
-				TitleSlug string `json:"titleSlug"`
-			} `json:"question"`
-		} `json:"activeDailyCodingChallengeQuestion"`
+	codeSnippet struct {
+		LangSlug string
+		Code     string
+	}
+
+	problemTitleMap struct {
+		Title           string `json:"title"`
+		TitleSlug       string `json:"titleSlug"`
+		ID              string `json:"frontendQuestionId"`
+		TranslatedTitle string `json:"translatedTitle,omitempty"` // leetcode.cn only
 	}
 
 	metaData struct {
@@ -169,83 +133,146 @@ type (
 	envInfo map[string][]string // langSlug => [{lang, description}...]
 )
 
-func (c *Client) getDailyProblemTitle() (string, error) {
-	req, err := c.newRequest(dailyProblemQuery, nil)
+func (c *Client) getDailyProblemTitle(ctx context.Context) (string, error) {
+	req, err := c.newRequest(ctx, c.site.dailyProblemQuery, nil)
 	if err != nil {
 		return "", fmt.Errorf("init request: %w", err)
 	}
-	c.addRefererHeader(req, problemListReferer)
+	c.addRefererHeader(req, c.baseURL+c.site.problemListReferer)
 
 	data, err := c.doRequest(req)
 	if err != nil {
 		return "", fmt.Errorf("query: %w", err)
 	}
 
-	parsedResponse := &dailyChallengeResponse{}
-	if err = json.Unmarshal(data, parsedResponse); err != nil {
-		return "", fmt.Errorf("response unmarshal: %w", err)
-	}
-
-	return parsedResponse.Challenge.Question.TitleSlug, nil
+	return c.site.parseDailyProblemTitle(data)
 }
 
-func (c *Client) getProblemDataByTitleSlug(titleSlug string) (*problemData, error) {
-	req, err := c.newRequest(problemByTitleSlugQuery, map[string]interface{}{
+func (c *Client) getProblemDataByTitleSlug(ctx context.Context, titleSlug string) (*problemData, error) {
+	req, err := c.newRequest(ctx, c.site.problemByTitleSlugQuery, map[string]interface{}{
 		variableTitleSlug: titleSlug,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("init request: %w", err)
 	}
-	c.addRefererHeader(req, fmt.Sprintf(problemRefererTemplate, titleSlug))
+	c.addRefererHeader(req, c.baseURL+fmt.Sprintf(problemRefererTemplate, titleSlug))
 
 	data, err := c.doRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
 
-	fmt.Printf("DATA: %v\n", string(data))
-
-	parsedResponse := &problemDataResponseWrapper{}
+	parsedResponse := &problemByTitleSlugQueryData{}
+	translation := &translatedQuestionData{}
 	if err = json.Unmarshal(data, parsedResponse); err != nil {
 		return nil, fmt.Errorf("response unmarshal: %w", err)
 	}
+	if err = json.Unmarshal(data, translation); err != nil {
+		return nil, fmt.Errorf("response unmarshal: %w", err)
+	}
+	// unknown slugs come back as a null question without any errors
+	question := parsedResponse.problemData(translation.QuestionData)
+	if question == nil {
+		return nil, fmt.Errorf("question %s: %w", titleSlug, ErrorProblemNotFound)
+	}
 
-	err = c.parseAdditionalData(&parsedResponse.Question)
+	err = c.parseAdditionalData(question)
 	if err != nil {
 		return nil, fmt.Errorf("parse fields: %w", err)
 	}
 
-	return &parsedResponse.Question, nil
+	return question, nil
 }
 
+// problemData converts the question of the response, nil if there is none.
+func (d *problemByTitleSlugQueryData) problemData(translation translatedQuestion) *problemData {
+	q := d.QuestionData
+	if q == nil {
+		return nil
+	}
+
+	data := &problemData{
+		ID:                  q.QuestionID,
+		FrontendID:          q.QuestionFrontendID,
+		Title:               q.Title,
+		TitleSlug:           q.TitleSlug,
+		Likes:               q.Likes,
+		Dislikes:            q.Dislikes,
+		ACRate:              q.AcRate,
+		TopicTags:           make([]topicTag, 0, len(q.TopicTags)),
+		TranslatedTitle:     translation.TranslatedTitle,
+		TranslatedContent:   translation.TranslatedContent,
+		ExampleTestcases:    q.ExampleTestcases,
+		CodeSnippets:        make([]codeSnippet, 0, len(q.CodeSnippets)),
+		Content:             q.Content,
+		IsPaidOnly:          q.IsPaidOnly,
+		CanSeeQuestion:      q.CanSeeQuestion,
+		Difficulty:          q.Difficulty,
+		CategoryTitle:       q.CategoryTitle,
+		Hints:               q.Hints,
+		MetaDataRaw:         q.MetaData,
+		StatsRaw:            q.Stats,
+		EnvInfoRaw:          q.EnvInfo,
+		SimilarQuestionsRaw: q.SimilarQuestions,
+	}
+	for _, t := range q.TopicTags {
+		data.TopicTags = append(data.TopicTags, topicTag(t))
+	}
+	for _, cs := range q.CodeSnippets {
+		data.CodeSnippets = append(data.CodeSnippets, codeSnippet(cs))
+	}
+	if q.CompanyTagStats != "" {
+		companyTagStats := q.CompanyTagStats
+		data.CompanyTagStatsRaw = &companyTagStats
+	}
+	return data
+}
+
+// parseAdditionalData decodes fields the API returns as JSON-encoded strings.
+// Missing fields are left empty, malformed ones fail the whole problem.
 func (c *Client) parseAdditionalData(data *problemData) error {
-	err := json.Unmarshal([]byte(data.MetaDataRaw), &data.MetaData)
-	if err != nil {
+	if err := unmarshalJSONString(data.MetaDataRaw, &data.MetaData); err != nil {
 		return fmt.Errorf("unmarshal metadata: %w", err)
 	}
-	err = json.Unmarshal([]byte(data.StatsRaw), &data.Stats)
-	if err != nil {
+	if err := unmarshalJSONString(data.StatsRaw, &data.Stats); err != nil {
 		return fmt.Errorf("unmarshal stats: %w", err)
 	}
-	err = json.Unmarshal([]byte(data.EnvInfoRaw), &data.EnvInfo)
-	if err != nil {
-		return fmt.Errorf("unmarshal metadata: %w", err)
+	if err := unmarshalJSONString(data.EnvInfoRaw, &data.EnvInfo); err != nil {
+		return fmt.Errorf("unmarshal env info: %w", err)
+	}
+	if err := unmarshalJSONString(data.SimilarQuestionsRaw, &data.SimilarQuestions); err != nil {
+		return fmt.Errorf("unmarshal similar questions: %w", err)
+	}
+	if data.CompanyTagStatsRaw != nil {
+		if err := unmarshalJSONString(*data.CompanyTagStatsRaw, &data.CompanyTagStats); err != nil {
+			return fmt.Errorf("unmarshal company tag stats: %w", err)
+		}
 	}
 	return nil
 }
 
-func (c *Client) refreshTitleSlugMaps() error {
-	problemCount, err := c.getTotalProblemCount()
+// unmarshalJSONString treats empty and "null" strings as absent values.
+func unmarshalJSONString(raw string, v interface{}) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), v)
+}
+
+func (c *Client) refreshTitleSlugMaps(ctx context.Context) error {
+	problemCount, err := c.getTotalProblemCount(ctx)
 	if err != nil {
 		return fmt.Errorf("get problem count: %w", err)
 	}
 	c.mu.RLock()
-	if len(c.problemIDMap) == problemCount {
-		c.mu.RUnlock()
+	idx := c.problemIndex
+	c.mu.RUnlock()
+	if idx != nil && idx.size() == problemCount {
 		return nil
 	}
 
-	req, err := c.newRequest(problemListQuery, map[string]interface{}{
+	req, err := c.newRequest(ctx, c.site.problemListQuery, map[string]interface{}{
 		variableCategorySlug: "",
 		variableFilters:      struct{}{},
 		variableLimit:        problemCount,
@@ -253,48 +280,78 @@ func (c *Client) refreshTitleSlugMaps() error {
 	if err != nil {
 		return fmt.Errorf("init request: %w", err)
 	}
-	c.addRefererHeader(req, problemListReferer)
+	c.addRefererHeader(req, c.baseURL+c.site.problemListReferer)
 
 	data, err := c.doRequest(req)
 	if err != nil {
 		return fmt.Errorf("query: %w", err)
 	}
 
-	parsedResponse := &problemReferenceList{}
+	parsedResponse := &problemListQueryData{}
 	if err = json.Unmarshal(data, parsedResponse); err != nil {
 		return fmt.Errorf("response unmarshal: %w", err)
 	}
+	if parsedResponse.ProblemsetQuestionList == nil || len(parsedResponse.ProblemsetQuestionList.Questions) == 0 {
+		return fmt.Errorf("empty problem list")
+	}
+	questions := parsedResponse.ProblemsetQuestionList.Questions
+	translatedTitles, err := decodeTranslatedTitles(data, len(questions))
+	if err != nil {
+		return fmt.Errorf("response unmarshal: %w", err)
+	}
+
+	refs := make([]problemTitleMap, 0, len(questions))
+	for i, q := range questions {
+		refs = append(refs, problemTitleMap{
+			Title:           q.Title,
+			TitleSlug:       q.TitleSlug,
+			ID:              q.FrontendQuestionID,
+			TranslatedTitle: translatedTitles[i],
+		})
+	}
+
+	// build the index aside and swap it in one go, readers either see the old or the new one
+	idx = newProblemIndex(refs)
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.problemIndex = idx
+	c.mu.Unlock()
+	c.saveProblemIndex(refs)
 
 	return nil
 }
 
-func (c *Client) getTotalProblemCount() (int, error) {
-	req, err := c.newRequest(totalProblemsQuery, map[string]interface{}{
+func (c *Client) getTotalProblemCount(ctx context.Context) (int, error) {
+	req, err := c.newRequest(ctx, c.site.totalProblemsQuery, map[string]interface{}{
 		"categorySlug": "",
 		"filters":      struct{}{},
 	})
 	if err != nil {
 		return 0, fmt.Errorf("init request: %w", err)
 	}
-	c.addRefererHeader(req, problemListReferer)
+	c.addRefererHeader(req, c.baseURL+c.site.problemListReferer)
 
 	data, err := c.doRequest(req)
 	if err != nil {
 		return 0, fmt.Errorf("query: %w", err)
 	}
 
-	parsedResponse := &totalProblemsData{}
+	parsedResponse := &totalProblemsQueryData{}
 	if err = json.Unmarshal(data, parsedResponse); err != nil {
 		return 0, fmt.Errorf("response unmarshal: %w", err)
 	}
+	if parsedResponse.ProblemsetQuestionList == nil {
+		return 0, fmt.Errorf("empty problem list")
+	}
 
-	return parsedResponse.QuestionList.TotalNum, nil
+	return parsedResponse.ProblemsetQuestionList.Total, nil
 }
 
-func (c *Client) newRequest(query string, variables queryVariables) (*http.Request, error) {
+func (c *Client) newRequest(ctx context.Context, query string, variables queryVariables) (*http.Request, error) {
+	if err := c.requireOnline(); err != nil {
+		return nil, err
+	}
+
 	q := graphQLRequest{
 		Query:     query,
 		Variables: variables,
@@ -305,45 +362,113 @@ func (c *Client) newRequest(query string, variables queryVariables) (*http.Reque
 		return nil, fmt.Errorf("marshal question request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", graphqlAPIEndpoint, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+c.site.graphqlEndpoint, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("initialize request: %w", err)
 	}
 
-	err = c.addCSRFHeaders(req)
+	err = c.addAuthHeaders(req)
 	if err != nil {
-		return nil, fmt.Errorf("add csrf headers: %w", err)
+		return nil, fmt.Errorf("add auth headers: %w", err)
 	}
 	c.addQueryHeaders(req)
 
 	return req, nil
 }
 
-func (c *Client) doRequest(req *http.Request) ([]byte, error) {
-	response, err := c.cli.Do(req)
+// newRESTRequest builds a request to a non-GraphQL endpoint, payload is sent as JSON unless nil.
+func (c *Client) newRESTRequest(ctx context.Context, method, path string, payload interface{}) (*http.Request, error) {
+	if err := c.requireOnline(); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader = http.NoBody
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 	if err != nil {
+		return nil, fmt.Errorf("initialize request: %w", err)
+	}
+
+	err = c.addAuthHeaders(req)
+	if err != nil {
+		return nil, fmt.Errorf("add auth headers: %w", err)
+	}
+	c.addQueryHeaders(req)
+
+	return req, nil
+}
+
+// doAttempt executes the request once and returns the response body processed by decode.
+// Request context is checked beforehand as mocked or custom http clients may ignore it.
+func (c *Client) doAttempt(req *http.Request, decode responseDecoder) ([]byte, error) {
+	if err := req.Context().Err(); err != nil {
 		return nil, fmt.Errorf("http request: %w", err)
 	}
-	body, err := io.ReadAll(response.Body)
+
+	response, err := c.send(req)
 	if err != nil {
-		return nil, fmt.Errorf("read response data: %w", err)
+		return nil, fmt.Errorf("http request: %w", err)
 	}
 	defer func() {
 		cErr := response.Body.Close()
 		if cErr != nil {
-			fmt.Printf("Error closing API response body: %s\n", err.Error())
+			c.logger.Printf("Error closing API response body: %s", cErr)
 		}
 	}()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response data: %w", err)
+	}
+
+	return decode(response, body)
+}
+
+// decodeGraphQLResponse returns the data field of the GraphQL response.
+func decodeGraphQLResponse(response *http.Response, body []byte) ([]byte, error) {
+	// GraphQL validation errors come with 400, anything else unsuccessful is reported by status
+	success := response.StatusCode < http.StatusMultipleChoices
+	if !success && response.StatusCode != http.StatusBadRequest {
+		return nil, newHTTPError(response, body)
+	}
 
 	dataField := &responseDataWrapper{}
-	if err = json.Unmarshal(body, dataField); err != nil {
+	if err := json.Unmarshal(body, dataField); err != nil {
+		if !success {
+			return nil, newHTTPError(response, body)
+		}
 		return nil, fmt.Errorf("response unmarshal: %w", err)
 	}
+	if len(dataField.Errors) > 0 {
+		return nil, &dataField.Errors[0]
+	}
+	if !success {
+		return nil, newHTTPError(response, body)
+	}
 
 	return dataField.Data, nil
 }
 
-func (c *Client) refreshCSRFToken() error {
+// decodeRESTResponse returns the whole body of a successful response.
+func decodeRESTResponse(response *http.Response, body []byte) ([]byte, error) {
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return nil, newHTTPError(response, body)
+	}
+	return body, nil
+}
+
+// refreshCSRFToken fetches an anonymous csrf token, clients with a session keep the session one.
+func (c *Client) refreshCSRFToken(ctx context.Context) error {
+	if c.session != nil {
+		return nil
+	}
+
 	c.mu.RLock()
 	csrf := c.csrf
 	c.mu.RUnlock()
@@ -351,20 +476,23 @@ func (c *Client) refreshCSRFToken() error {
 	if csrf != nil && csrf.Expires.After(time.Now().Add(-2*refreshCooldown)) {
 		return nil
 	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("request init: %w", err)
+	}
 
-	req, err := http.NewRequestWithContext(context.Background(), "GET", leetcodeURL, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL, http.NoBody)
 	if err != nil {
 		return fmt.Errorf("request init: %w", err)
 	}
 
-	res, err := c.cli.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return fmt.Errorf("http request: %w", err)
 	}
 	defer func() {
 		cErr := res.Body.Close()
 		if cErr != nil {
-			fmt.Printf("Error closing API response body: %s\n", err.Error())
+			c.logger.Printf("Error closing API response body: %s", cErr)
 		}
 	}()
 
@@ -386,13 +514,24 @@ func (c *Client) refreshCSRFToken() error {
 	return nil
 }
 
+// invalidateCSRFToken makes the next refreshCSRFToken call fetch a new token regardless of expiration.
+func (c *Client) invalidateCSRFToken() {
+	c.mu.Lock()
+	c.csrf = nil
+	c.mu.Unlock()
+}
+
 func (c *Client) addCSRFHeaders(req *http.Request) error {
-	if c.csrf == nil {
+	c.mu.RLock()
+	csrf := c.csrf
+	c.mu.RUnlock()
+
+	if csrf == nil {
 		return fmt.Errorf("csrf token is empty")
 	}
 
-	req.AddCookie(c.csrf)
-	req.Header.Set("x-csrftoken", c.csrf.Value)
+	req.AddCookie(csrf)
+	req.Header.Set(csrfTokenHeader, csrf.Value)
 
 	return nil
 }
@@ -1,9 +1,11 @@
 package graphqlapiservice
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
@@ -20,16 +22,27 @@ type testSuite struct {
 func newMockClient(t *testing.T) testSuite {
 	ctrl := gomock.NewController(t)
 	cli := NewMockhttpClient(ctrl)
+	api, err := NewAPIClient(
+		WithHTTPClient(cli),
+		WithRetryPolicy(RetryPolicy{}),
+		WithRateLimit(0, 0),
+		WithLogger(nil),
+	)
+	assert.NoError(t, err)
 	return testSuite{
-		api: &Client{
-			cli:          cli,
-			problemCache: newCache(),
-		},
+		api:     api,
 		httpCli: cli,
 		ctrl:    ctrl,
 	}
 }
 
+func newJSONResponse(status int, body string) *http.Response {
+	recorder := httptest.NewRecorder()
+	recorder.WriteHeader(status)
+	recorder.Body.WriteString(body)
+	return recorder.Result()
+}
+
 func TestUnit_DoRequest(t *testing.T) {
 	s := newMockClient(t)
 	defer s.ctrl.Finish()
@@ -72,6 +85,23 @@ func TestUnit_DoRequest(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, err := s.api.newRequest(ctx, dailyProblemQuery, nil)
+		assert.Error(t, err) // no csrf token yet
+		assert.Nil(t, req)
+
+		s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+		req, err = s.api.newRequest(ctx, dailyProblemQuery, nil)
+		assert.NoError(t, err)
+
+		// no http call expected
+		body, err := s.api.doRequest(req)
+		assert.Nil(t, body)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
 }
 
 func TestUnit_RefreshCSRFToken(t *testing.T) {
@@ -118,7 +148,7 @@ func TestUnit_RefreshCSRFToken(t *testing.T) {
 			if test.mock != nil {
 				test.mock()
 			}
-			err := s.api.refreshCSRFToken()
+			err := s.api.refreshCSRFToken(context.Background())
 			if test.err != nil {
 				assert.Error(t, err)
 				assert.Nil(t, s.api.csrf)
@@ -129,3 +159,51 @@ func TestUnit_RefreshCSRFToken(t *testing.T) {
 		})
 	}
 }
+
+func TestUnit_ParseAdditionalData(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+
+	companyStats := `{"1":[{"name":"Amazon","slug":"amazon","timesEncountered":3}]}`
+	data := &problemData{
+		MetaDataRaw:         `{"name":"twoSum","params":[{"name":"nums","type":"integer[]"}],"return":{"type":"integer[]"}}`,
+		StatsRaw:            `{"totalAcceptedRaw":10,"totalSubmissionRaw":20}`,
+		EnvInfoRaw:          `{"golang":["Go","<golang env info>"]}`,
+		SimilarQuestionsRaw: `[{"title":"3Sum","titleSlug":"3sum","difficulty":"Medium","translatedTitle":null}]`,
+		CompanyTagStatsRaw:  &companyStats,
+	}
+
+	assert.NoError(t, s.api.parseAdditionalData(data))
+	assert.Equal(t, []similarQuestion{{Title: "3Sum", TitleSlug: "3sum", Difficulty: "Medium"}}, data.SimilarQuestions)
+	assert.Equal(t, companyTagStats{"1": {{Name: "Amazon", Slug: "amazon", TimesEncountered: 3}}}, data.CompanyTagStats)
+
+	data.SimilarQuestionsRaw = "not json"
+	assert.Error(t, s.api.parseAdditionalData(data))
+
+	// optional fields may be missing altogether
+	null := "null"
+	assert.NoError(t, s.api.parseAdditionalData(&problemData{CompanyTagStatsRaw: &null}))
+}
+
+func TestUnit_ProblemQueryRequestsAllFields(t *testing.T) {
+	// fields Problem is built from, listed by hand so that a field dropped from a query fails the test
+	problemFields := []string{
+		"questionId", "questionFrontendId", "title", "titleSlug", "likes", "dislikes",
+		"topicTags", "name", "slug", "similarQuestions", "exampleTestcases", "codeSnippets", "langSlug", "code",
+		"content", "isPaidOnly", "canSeeQuestion", "difficulty", "categoryTitle", "hints", "metaData", "stats", "envInfo",
+	}
+	// fields that exist in the schema of a single site
+	siteFields := map[Site][]string{
+		SiteGlobal: {"acRate", "companyTagStats"},
+		SiteChina:  {"translatedTitle", "translatedContent"},
+	}
+
+	for site, config := range sites {
+		t.Run(string(site), func(t *testing.T) {
+			for _, name := range append(append([]string(nil), problemFields...), siteFields[site]...) {
+				pattern := regexp.MustCompile(`(?m)^\s*` + name + `\b`)
+				assert.Regexp(t, pattern, config.problemByTitleSlugQuery, "field %s is not requested", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+package graphqlapiservice
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/content"
+)
+
+const (
+	ConstraintOther   ConstraintKind = "" // not recognized, see Constraint.Raw
+	ConstraintRange   ConstraintKind = "range"
+	ConstraintCharset ConstraintKind = "charset"
+
+	constraintsHeader = "Constraints:"
+	listItemMarker    = "- " // of the text rendering
+	printableASCII    = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+	lowercaseLetters  = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseLetters  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits            = "0123456789"
+)
+
+var (
+	comparison    = regexp.MustCompile(`\s*(<=|>=|==|!=|<|>|≤|≥)\s*`)
+	rangeOf       = regexp.MustCompile(`^(.+?) (?:is|are) (?:in )?(?:the )?range \[(.+?),\s*(.+)\]$`)
+	charsetOf     = regexp.MustCompile(`^(.+?) (?:consists? (?:only )?of|contains? only|(?:is|are) (?:either|one of)) (.+)$`)
+	quotedChar    = regexp.MustCompile(`'(.)'|"(.)"`)
+	leadingName   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+	targetExpr    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_]+|\[[A-Za-z0-9_]+\])*$`)
+	thousandsSep  = regexp.MustCompile(`(\d),(\d{3})\b`)
+	subjectSep    = regexp.MustCompile(`\s*,\s*|\s+and\s+`)
+	charsetFiller = regexp.MustCompile(`\b(only|and|or|of|the|either|characters?|english)\b|[,.]`)
+
+	// longest phrases first, "english letters" is part of "lowercase english letters"
+	charsetPhrases = []struct {
+		phrase string
+		chars  string
+	}{
+		{"printable ascii", printableASCII},
+		{"lowercase english letters", lowercaseLetters},
+		{"uppercase english letters", uppercaseLetters},
+		{"lowercase letters", lowercaseLetters},
+		{"uppercase letters", uppercaseLetters},
+		{"english letters", lowercaseLetters + uppercaseLetters},
+		{"letters", lowercaseLetters + uppercaseLetters},
+		{"digits", digits},
+		{"spaces", " "},
+		{"space", " "},
+	}
+
+	nodeTypes = map[string]bool{"ListNode": true, "TreeNode": true}
+)
+
+type (
+	ConstraintKind string
+
+	// Constraint is a line of the Constraints section of the statement, e.g. "1 <= nums.length <= 10^4".
+	// A line constraining several parameters at once, e.g. "1 <= m, n <= 200", yields a constraint per parameter.
+	Constraint struct {
+		Raw       string
+		Parameter string // name of the constrained input parameter, empty if none matches
+		Kind      ConstraintKind
+		Target    string // constrained expression, e.g. "nums.length" or "nums[i]"
+
+		Min, Max *Bound // range constraints only, nil if unbounded
+		Charset  string // charset constraints only, characters the target consists of in ascending order
+	}
+
+	Bound struct {
+		Expr      string  // as in the statement, e.g. "10^4", "2^31 - 1" or "nums.length"
+		Value     float64 // valid if Known
+		Known     bool    // Expr is a number rather than a reference to other parameters
+		Inclusive bool
+	}
+)
+
+// Constraints parses the Constraints section of the statement. Lines that aren't recognized are kept as
+// ConstraintOther with their raw text, attached to the parameter they mention if any.
+func (p *Problem) Constraints() []Constraint {
+	var constraints []Constraint
+	for _, line := range constraintLines(content.Text(p.Content, 0)) {
+		constraints = append(constraints, parseConstraint(line, p.MetaData.InputParameters)...)
+	}
+	return constraints
+}
+
+// ParameterConstraints returns the constraints of one of MetaData.InputParameters. Constraints are derived
+// from Content on demand like TestCases, so they are looked up by parameter rather than kept in Parameter,
+// which mirrors the metadata of the API.
+func (p *Problem) ParameterConstraints(param Parameter) []Constraint {
+	var constraints []Constraint
+	for _, c := range p.Constraints() {
+		if c.Parameter != "" && c.Parameter == param.Name {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints
+}
+
+// constraintLines returns items of the list following the Constraints header of the text rendering.
+func constraintLines(text string) []string {
+	var lines []string
+	inSection := false
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.TrimSpace(line) == constraintsHeader:
+			inSection = true
+		case !inSection || line == "":
+		case strings.HasPrefix(line, listItemMarker):
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, listItemMarker)))
+		case strings.HasPrefix(line, " "):
+			// nested content of the item
+		default:
+			inSection = false
+		}
+	}
+	return lines
+}
+
+func parseConstraint(raw string, params []Parameter) []Constraint {
+	line := strings.TrimSuffix(strings.TrimSpace(raw), ".")
+
+	if m := rangeOf.FindStringSubmatch(line); m != nil {
+		if cs := rangeConstraints(raw, m[1], params, bound(m[2], true), bound(m[3], true)); cs != nil {
+			return cs
+		}
+	}
+	if m := charsetOf.FindStringSubmatch(line); m != nil {
+		if charset, ok := parseCharset(m[2]); ok {
+			var cs []Constraint
+			for _, target := range subjectSep.Split(m[1], -1) {
+				cs = append(cs, Constraint{
+					Raw:       raw,
+					Parameter: matchParameter(target, params),
+					Kind:      ConstraintCharset,
+					Target:    target,
+					Charset:   charset,
+				})
+			}
+			return cs
+		}
+	}
+	if cs := comparisonConstraints(raw, line, params); cs != nil {
+		return cs
+	}
+
+	return []Constraint{{Raw: raw, Parameter: mentionedParameter(line, params)}}
+}
+
+// comparisonConstraints parses chains like "1 <= nums.length <= 10^4", "n == height.length" or "0 < x".
+func comparisonConstraints(raw, line string, params []Parameter) []Constraint {
+	terms := comparison.Split(line, -1)
+	ops := comparison.FindAllStringSubmatch(line, -1)
+	if len(terms) < 2 || len(terms) > 3 {
+		return nil
+	}
+
+	// the target is the term referencing a parameter, or the only one that isn't a number
+	target := -1
+	for i, term := range terms {
+		if matchParameter(term, params) != "" {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		for i, term := range terms {
+			if _, ok := evaluate(term); !ok {
+				target = i
+				break
+			}
+		}
+	}
+	if target < 0 || (len(terms) == 3 && target != 1) {
+		return nil
+	}
+	for _, name := range subjectSep.Split(terms[target], -1) {
+		if !targetExpr.MatchString(name) {
+			// prose like "Either x is not zero or n > 0"
+			return nil
+		}
+	}
+
+	var lower, upper *Bound
+	for i, op := range ops {
+		// op i is between terms i and i+1
+		var other string
+		targetOnLeft := false
+		switch target {
+		case i:
+			other, targetOnLeft = terms[i+1], true
+		case i + 1:
+			other = terms[i]
+		default:
+			return nil
+		}
+
+		operator := op[1]
+		if targetOnLeft {
+			operator = flipComparison(operator)
+		}
+		// now read as "other operator target"
+		switch operator {
+		case "<=", "≤":
+			lower = bound(other, true)
+		case "<":
+			lower = bound(other, false)
+		case ">=", "≥":
+			upper = bound(other, true)
+		case ">":
+			upper = bound(other, false)
+		case "==":
+			lower, upper = bound(other, true), bound(other, true)
+		default:
+			return nil
+		}
+	}
+
+	return rangeConstraints(raw, terms[target], params, lower, upper)
+}
+
+func rangeConstraints(raw, targets string, params []Parameter, lower, upper *Bound) []Constraint {
+	var cs []Constraint
+	for _, target := range subjectSep.Split(strings.TrimSpace(targets), -1) {
+		if target == "" {
+			return nil
+		}
+		cs = append(cs, Constraint{
+			Raw:       raw,
+			Parameter: matchParameter(target, params),
+			Kind:      ConstraintRange,
+			Target:    target,
+			Min:       lower,
+			Max:       upper,
+		})
+	}
+	return cs
+}
+
+func flipComparison(op string) string {
+	switch op {
+	case "<=", "≤":
+		return ">="
+	case "<":
+		return ">"
+	case ">=", "≥":
+		return "<="
+	case ">":
+		return "<"
+	}
+	return op
+}
+
+func bound(expr string, inclusive bool) *Bound {
+	expr = strings.TrimSpace(expr)
+	b := &Bound{Expr: expr, Inclusive: inclusive}
+	b.Value, b.Known = evaluate(expr)
+	return b
+}
+
+// matchParameter returns the parameter the expression starts with, e.g. nums of "nums[i]".
+// Node.val and the number of nodes refer to the list or tree parameter.
+func matchParameter(expr string, params []Parameter) string {
+	expr = strings.TrimSpace(expr)
+	name := leadingName.FindString(expr)
+	for _, param := range params {
+		if param.Name == name {
+			return param.Name
+		}
+	}
+	if name == "Node" || strings.Contains(strings.ToLower(expr), "number of nodes") {
+		for _, param := range params {
+			if nodeTypes[param.Type] {
+				return param.Name
+			}
+		}
+	}
+	return ""
+}
+
+// mentionedParameter returns the first parameter named in the line as a whole word.
+func mentionedParameter(line string, params []Parameter) string {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(line, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = true
+	}
+	for _, param := range params {
+		if words[param.Name] {
+			return param.Name
+		}
+	}
+	return ""
+}
+
+// parseCharset turns descriptions like "lowercase English letters and digits" or "'(' and ')'" into characters.
+func parseCharset(description string) (string, bool) {
+	chars := make(map[rune]bool)
+	rest := quotedChar.ReplaceAllStringFunc(description, func(q string) string {
+		for _, c := range q[1 : len(q)-1] {
+			chars[c] = true
+		}
+		return " "
+	})
+
+	rest = strings.ToLower(rest)
+	for _, p := range charsetPhrases {
+		if strings.Contains(rest, p.phrase) {
+			for _, c := range p.chars {
+				chars[c] = true
+			}
+			rest = strings.ReplaceAll(rest, p.phrase, " ")
+		}
+	}
+	if len(chars) == 0 || strings.TrimSpace(charsetFiller.ReplaceAllString(rest, " ")) != "" {
+		return "", false
+	}
+
+	sorted := make([]rune, 0, len(chars))
+	for c := range chars {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return string(sorted), true
+}
+
+// evaluate computes bounds like "10^4", "-2^31", "2^31 - 1", "3 * 10^4" or "10,000".
+func evaluate(expr string) (float64, bool) {
+	for thousandsSep.MatchString(expr) {
+		expr = thousandsSep.ReplaceAllString(expr, "$1$2")
+	}
+	e := &evaluator{s: strings.ReplaceAll(expr, " ", "")}
+	if e.s == "" {
+		return 0, false
+	}
+	v, err := e.sum()
+	if err != nil || e.pos != len(e.s) || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, false
+	}
+	return v, true
+}
+
+// evaluator is a recursive descent parser of arithmetic expressions, ^ binds tighter than unary minus.
+type evaluator struct {
+	s   string
+	pos int
+}
+
+func (e *evaluator) sum() (float64, error) {
+	v, err := e.product()
+	for err == nil && e.pos < len(e.s) && (e.s[e.pos] == '+' || e.s[e.pos] == '-') {
+		op := e.s[e.pos]
+		e.pos++
+		var rhs float64
+		if rhs, err = e.product(); op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+	return v, err
+}
+
+func (e *evaluator) product() (float64, error) {
+	v, err := e.unary()
+	for err == nil && e.pos < len(e.s) && (e.s[e.pos] == '*' || strings.HasPrefix(e.s[e.pos:], "×")) {
+		if e.s[e.pos] == '*' {
+			e.pos++
+		} else {
+			e.pos += len("×")
+		}
+		var rhs float64
+		rhs, err = e.unary()
+		v *= rhs
+	}
+	return v, err
+}
+
+func (e *evaluator) unary() (float64, error) {
+	if e.pos < len(e.s) && e.s[e.pos] == '-' {
+		e.pos++
+		v, err := e.unary()
+		return -v, err
+	}
+	return e.power()
+}
+
+func (e *evaluator) power() (float64, error) {
+	base, err := e.primary()
+	if err != nil || e.pos >= len(e.s) || e.s[e.pos] != '^' {
+		return base, err
+	}
+	e.pos++
+	exp, err := e.unary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exp), nil
+}
+
+func (e *evaluator) primary() (float64, error) {
+	if e.pos < len(e.s) && e.s[e.pos] == '(' {
+		e.pos++
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if e.pos >= len(e.s) || e.s[e.pos] != ')' {
+			return 0, fmt.Errorf("unbalanced parentheses")
+		}
+		e.pos++
+		return v, nil
+	}
+
+	start := e.pos
+	for e.pos < len(e.s) && (e.s[e.pos] >= '0' && e.s[e.pos] <= '9' || e.s[e.pos] == '.') {
+		e.pos++
+	}
+	if start == e.pos {
+		return 0, fmt.Errorf("number expected at %d", start)
+	}
+	return strconv.ParseFloat(e.s[start:e.pos], 64)
+}
@@ -0,0 +1,230 @@
+package graphqlapiservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Constraints(t *testing.T) {
+	testCases := map[string]struct {
+		problem  Problem
+		expected []Constraint
+	}{
+		"ranges and unrecognized lines": {
+			problem: Problem{
+				Content:  readStatement(t, "two-sum"),
+				MetaData: MetaData{InputParameters: []Parameter{{Name: "nums", Type: "integer[]"}, {Name: "target", Type: "integer"}}},
+			},
+			expected: []Constraint{
+				{
+					Raw: "2 <= nums.length <= 10^4", Parameter: "nums", Kind: ConstraintRange, Target: "nums.length",
+					Min: &Bound{Expr: "2", Value: 2, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "10^4", Value: 1e4, Known: true, Inclusive: true},
+				},
+				{
+					Raw: "-10^9 <= nums[i] <= 10^9", Parameter: "nums", Kind: ConstraintRange, Target: "nums[i]",
+					Min: &Bound{Expr: "-10^9", Value: -1e9, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "10^9", Value: 1e9, Known: true, Inclusive: true},
+				},
+				{
+					Raw: "-10^9 <= target <= 10^9", Parameter: "target", Kind: ConstraintRange, Target: "target",
+					Min: &Bound{Expr: "-10^9", Value: -1e9, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "10^9", Value: 1e9, Known: true, Inclusive: true},
+				},
+				{Raw: "Only one valid answer exists."},
+			},
+		},
+		"exclusive bounds and expressions": {
+			problem: Problem{
+				Content:  readStatement(t, "powx-n"),
+				MetaData: MetaData{InputParameters: []Parameter{{Name: "x", Type: "double"}, {Name: "n", Type: "integer"}}},
+			},
+			expected: []Constraint{
+				{
+					Raw: "-100.0 < x < 100.0", Parameter: "x", Kind: ConstraintRange, Target: "x",
+					Min: &Bound{Expr: "-100.0", Value: -100, Known: true},
+					Max: &Bound{Expr: "100.0", Value: 100, Known: true},
+				},
+				{
+					Raw: "-2^31 <= n <= 2^31-1", Parameter: "n", Kind: ConstraintRange, Target: "n",
+					Min: &Bound{Expr: "-2^31", Value: -2147483648, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "2^31-1", Value: 2147483647, Known: true, Inclusive: true},
+				},
+				{Raw: "n is an integer.", Parameter: "n"},
+				{Raw: "Either x is not zero or n > 0.", Parameter: "x"},
+				{Raw: "-10^4 <= x^n <= 10^4", Parameter: "x"},
+			},
+		},
+		"nodes": {
+			problem: Problem{
+				Content:  readStatement(t, "reverse-linked-list"),
+				MetaData: MetaData{InputParameters: []Parameter{{Name: "head", Type: "ListNode"}}},
+			},
+			expected: []Constraint{
+				{
+					Raw: "The number of nodes in the list is the range [0, 5000].", Parameter: "head", Kind: ConstraintRange,
+					Target: "The number of nodes in the list",
+					Min:    &Bound{Expr: "0", Value: 0, Known: true, Inclusive: true},
+					Max:    &Bound{Expr: "5000", Value: 5000, Known: true, Inclusive: true},
+				},
+				{
+					Raw: "-5000 <= Node.val <= 5000", Parameter: "head", Kind: ConstraintRange, Target: "Node.val",
+					Min: &Bound{Expr: "-5000", Value: -5000, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "5000", Value: 5000, Known: true, Inclusive: true},
+				},
+			},
+		},
+		"no constraints": {
+			problem: Problem{Content: "<p>Statement.</p>"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.problem.Constraints())
+		})
+	}
+}
+
+func TestUnit_ParameterConstraints(t *testing.T) {
+	nums := Parameter{Name: "nums", Type: "integer[]"}
+	p := Problem{
+		Content:  readStatement(t, "two-sum"),
+		MetaData: MetaData{InputParameters: []Parameter{nums, {Name: "target", Type: "integer"}}},
+	}
+
+	var targets []string
+	for _, c := range p.ParameterConstraints(nums) {
+		targets = append(targets, c.Target)
+	}
+	assert.Equal(t, []string{"nums.length", "nums[i]"}, targets)
+	assert.Empty(t, p.ParameterConstraints(Parameter{Name: "k"}))
+}
+
+func TestUnit_ParseConstraint(t *testing.T) {
+	params := []Parameter{{Name: "s", Type: "string"}, {Name: "t", Type: "string"}, {Name: "grid", Type: "integer[][]"}}
+
+	testCases := map[string]struct {
+		line     string
+		expected []Constraint
+	}{
+		"charset": {
+			line: "s consists of lowercase English letters.",
+			expected: []Constraint{
+				{Raw: "s consists of lowercase English letters.", Parameter: "s", Kind: ConstraintCharset, Target: "s", Charset: lowercaseLetters},
+			},
+		},
+		"charset of several parameters": {
+			line: "s and t consist only of digits and '+'",
+			expected: []Constraint{
+				{Raw: "s and t consist only of digits and '+'", Parameter: "s", Kind: ConstraintCharset, Target: "s", Charset: "+" + digits},
+				{Raw: "s and t consist only of digits and '+'", Parameter: "t", Kind: ConstraintCharset, Target: "t", Charset: "+" + digits},
+			},
+		},
+		"charset of elements": {
+			line: "s[i] is either '0' or '1'.",
+			expected: []Constraint{
+				{Raw: "s[i] is either '0' or '1'.", Parameter: "s", Kind: ConstraintCharset, Target: "s[i]", Charset: "01"},
+			},
+		},
+		"unknown charset": {
+			line:     "s consists of valid IPv4 addresses.",
+			expected: []Constraint{{Raw: "s consists of valid IPv4 addresses.", Parameter: "s"}},
+		},
+		"several targets and thousands separators": {
+			line: "1 <= m, n <= 10,000",
+			expected: []Constraint{
+				{
+					Raw: "1 <= m, n <= 10,000", Kind: ConstraintRange, Target: "m",
+					Min: &Bound{Expr: "1", Value: 1, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "10,000", Value: 1e4, Known: true, Inclusive: true},
+				},
+				{
+					Raw: "1 <= m, n <= 10,000", Kind: ConstraintRange, Target: "n",
+					Min: &Bound{Expr: "1", Value: 1, Known: true, Inclusive: true},
+					Max: &Bound{Expr: "10,000", Value: 1e4, Known: true, Inclusive: true},
+				},
+			},
+		},
+		"reference to another expression": {
+			line: "m == grid.length",
+			expected: []Constraint{
+				{
+					Raw: "m == grid.length", Parameter: "grid", Kind: ConstraintRange, Target: "grid.length",
+					Min: &Bound{Expr: "m", Inclusive: true},
+					Max: &Bound{Expr: "m", Inclusive: true},
+				},
+			},
+		},
+		"single bound": {
+			line: "grid[i][j] >= 1",
+			expected: []Constraint{
+				{Raw: "grid[i][j] >= 1", Parameter: "grid", Kind: ConstraintRange, Target: "grid[i][j]", Min: &Bound{Expr: "1", Value: 1, Known: true, Inclusive: true}},
+			},
+		},
+		"chain of four": {
+			line:     "0 <= i < j < s.length",
+			expected: []Constraint{{Raw: "0 <= i < j < s.length", Parameter: "s"}},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseConstraint(test.line, params))
+		})
+	}
+}
+
+func TestUnit_MentionedParameter(t *testing.T) {
+	params := []Parameter{{Name: "s"}, {Name: "n"}, {Name: "word_list"}}
+
+	testCases := map[string]struct {
+		line     string
+		expected string
+	}{
+		"whole word":          {line: "s is not empty.", expected: "s"},
+		"part of other words": {line: "nums contains distinct values."},
+		"expression":          {line: "-10^4 <= s.length * n^2", expected: "s"},
+		"underscore":          {line: "word_list[i] is unique.", expected: "word_list"},
+		"first parameter":     {line: "n is at most the length of s.", expected: "s"},
+		"none":                {line: "Only one valid answer exists."},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, mentionedParameter(test.line, params))
+		})
+	}
+}
+
+func TestUnit_Evaluate(t *testing.T) {
+	testCases := map[string]struct {
+		expr     string
+		expected float64
+		ok       bool
+	}{
+		"power":               {expr: "10^4", expected: 1e4, ok: true},
+		"negative power":      {expr: "-2^31", expected: -2147483648, ok: true},
+		"negative exponent":   {expr: "10^-5", expected: 1e-5, ok: true},
+		"product":             {expr: "3 * 10^4", expected: 3e4, ok: true},
+		"difference":          {expr: "2^31 - 1", expected: 2147483647, ok: true},
+		"fractional exponent": {expr: "4^0.5", expected: 2, ok: true},
+		"nested exponent":     {expr: "2^3^2", expected: 512, ok: true},
+		"overflow":            {expr: "2^10^18"},
+		"parentheses":         {expr: "(10^5 + 1) / 2"},
+		"reference":           {expr: "n - 1"},
+		"unbalanced":          {expr: "(1 + 2"},
+		"empty":               {expr: ""},
+		"decimal":             {expr: "100.0", expected: 100, ok: true},
+		"nested parentheses":  {expr: "2 * (3 + (4 - 1))", expected: 12, ok: true},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := evaluate(test.expr)
+			assert.Equal(t, test.ok, ok)
+			assert.InDelta(t, test.expected, actual, 1e-12)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package graphqlapiservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskCacheVersion  = problemVersion // of problem files
+	diskCacheFileExt  = ".json"
+	diskCacheAppDir   = "leetcode-tools"
+	diskCacheDirPerm  = 0o755
+	diskCacheTempName = ".tmp-*"
+
+	diskCacheMetaDir     = "meta" // never clashes with problem files, which all have the extension
+	diskCacheDailyFile   = "daily.json"
+	diskCacheIndexFile   = "index.json"
+	diskCacheMetaVersion = 1 // of meta files, independent of problems so that the daily history survives upgrades
+)
+
+type (
+	// DiskCache keeps problems as JSON files, one per title slug, so that they survive restarts.
+	// Expired entries are kept on disk and are still available with Entry.
+	// Besides problems it keeps the problem index and the history of daily problems for offline clients.
+	DiskCache struct {
+		dir string
+		ttl time.Duration
+		now func() time.Time
+
+		metaMu sync.Mutex // serializes read-modify-write of meta files
+	}
+
+	// CacheEntry is a cached problem with its freshness metadata.
+	CacheEntry struct {
+		Problem   Problem
+		FetchedAt time.Time
+		Expires   time.Time // zero for entries that never expire
+	}
+
+	diskEntry struct {
+		Version   int       `json:"version"`
+		FetchedAt time.Time `json:"fetchedAt"`
+		Expires   time.Time `json:"expires,omitempty"`
+		Problem   Problem   `json:"problem"`
+	}
+
+	diskDailyHistory struct {
+		Version int               `json:"version"`
+		Daily   map[string]string `json:"daily"` // date => title slug
+	}
+
+	diskProblemIndex struct {
+		Version   int               `json:"version"`
+		SavedAt   time.Time         `json:"savedAt"`
+		Questions []problemTitleMap `json:"questions"`
+	}
+)
+
+// DefaultDiskCacheDir returns leetcode-tools directory in the user cache directory.
+func DefaultDiskCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("user cache dir: %w", err)
+	}
+	return filepath.Join(dir, diskCacheAppDir), nil
+}
+
+// NewDiskCache creates the directory if needed, non-positive ttl keeps entries fresh forever.
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, diskCacheDirPerm); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &DiskCache{
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}, nil
+}
+
+// Stale reports whether the entry has expired by now.
+func (e CacheEntry) Stale(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// Get returns fresh problems only.
+func (c *DiskCache) Get(titleSlug string) (Problem, bool) {
+	e, ok := c.Entry(titleSlug)
+	if !ok || e.Stale(c.now()) {
+		return Problem{}, false
+	}
+	return e.Problem, true
+}
+
+// Entry returns the cached problem even if it has expired. Unreadable entries and entries
+// written by other versions of the package are removed and reported as missing.
+func (c *DiskCache) Entry(titleSlug string) (CacheEntry, bool) {
+	path, ok := c.path(titleSlug)
+	if !ok {
+		return CacheEntry{}, false
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from the escaped title slug
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	e := &diskEntry{}
+	if err = json.Unmarshal(data, e); err != nil || e.Version != diskCacheVersion || e.Problem.TitleSlug != titleSlug {
+		_ = os.Remove(path)
+		return CacheEntry{}, false
+	}
+
+	return CacheEntry{Problem: e.Problem, FetchedAt: e.FetchedAt, Expires: e.Expires}, true
+}
+
+// Add ignores write errors, the problem is simply requested again next time. Use Put to handle them.
+func (c *DiskCache) Add(p *Problem) {
+	_ = c.Put(p)
+}
+
+// Put writes the problem atomically, readers see either the previous entry or the new one.
+func (c *DiskCache) Put(p *Problem) error {
+	return c.put(p, c.now(), c.ttl)
+}
+
+func (c *DiskCache) addFrozen(p *Problem, fetchedAt time.Time) error {
+	return c.put(p, fetchedAt, 0)
+}
+
+// put writes the entry fetched at the given time, non-positive ttl keeps it fresh forever.
+func (c *DiskCache) put(p *Problem, fetchedAt time.Time, ttl time.Duration) error {
+	path, ok := c.path(p.TitleSlug)
+	if !ok {
+		return fmt.Errorf("invalid title slug %q", p.TitleSlug)
+	}
+
+	e := diskEntry{
+		Version:   diskCacheVersion,
+		FetchedAt: fetchedAt.UTC(),
+		Problem:   *p,
+	}
+	if ttl > 0 {
+		e.Expires = e.FetchedAt.Add(ttl)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+
+	return writeFileAtomic(c.dir, path, data)
+}
+
+// Remove deletes the entry, missing entries are not an error.
+func (c *DiskCache) Remove(titleSlug string) error {
+	path, ok := c.path(titleSlug)
+	if !ok {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove entry: %w", err)
+	}
+	return nil
+}
+
+func (c *DiskCache) dailyProblem(date string) (string, bool) {
+	h, ok := c.readDailyHistory()
+	if !ok {
+		return "", false
+	}
+	titleSlug, ok := h.Daily[date]
+	return titleSlug, ok
+}
+
+func (c *DiskCache) setDailyProblem(date, titleSlug string) error {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+
+	h, ok := c.readDailyHistory()
+	if !ok {
+		h = &diskDailyHistory{Version: diskCacheMetaVersion, Daily: make(map[string]string, 1)}
+	}
+	h.Daily[date] = titleSlug
+
+	return c.writeMeta(diskCacheDailyFile, h)
+}
+
+func (c *DiskCache) storedProblemIndex() ([]problemTitleMap, bool) {
+	idx := &diskProblemIndex{}
+	if !c.readMeta(diskCacheIndexFile, idx) || idx.Version != diskCacheMetaVersion {
+		return nil, false
+	}
+	return idx.Questions, true
+}
+
+func (c *DiskCache) setProblemIndex(refs []problemTitleMap) error {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+
+	return c.writeMeta(diskCacheIndexFile, diskProblemIndex{
+		Version:   diskCacheMetaVersion,
+		SavedAt:   c.now().UTC(),
+		Questions: refs,
+	})
+}
+
+func (c *DiskCache) readDailyHistory() (*diskDailyHistory, bool) {
+	h := &diskDailyHistory{}
+	if !c.readMeta(diskCacheDailyFile, h) || h.Version != diskCacheMetaVersion || h.Daily == nil {
+		return nil, false
+	}
+	return h, true
+}
+
+// readMeta reports missing and unreadable files alike, they are rewritten from scratch.
+func (c *DiskCache) readMeta(name string, v interface{}) bool {
+	data, err := os.ReadFile(filepath.Join(c.dir, diskCacheMetaDir, name)) //nolint:gosec // name is a constant
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func (c *DiskCache) writeMeta(name string, v interface{}) error {
+	dir := filepath.Join(c.dir, diskCacheMetaDir)
+	if err := os.MkdirAll(dir, diskCacheDirPerm); err != nil {
+		return fmt.Errorf("create meta dir: %w", err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", name, err)
+	}
+	return writeFileAtomic(dir, filepath.Join(dir, name), data)
+}
+
+// path escapes everything but the characters of regular title slugs, so that any slug maps to a file in dir.
+func (c *DiskCache) path(titleSlug string) (string, bool) {
+	if titleSlug == "" {
+		return "", false
+	}
+	name := strings.ReplaceAll(url.PathEscape(titleSlug), ".", "%2E")
+	return filepath.Join(c.dir, name+diskCacheFileExt), true
+}
+
+// writeFileAtomic writes data to a temporary file in dir and renames it over path.
+func writeFileAtomic(dir, path string, data []byte) error {
+	f, err := os.CreateTemp(dir, diskCacheTempName)
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) //nolint:errcheck // fails once renamed
+
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package graphqlapiservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_DiskCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewDiskCache(filepath.Join(dir, "problems"), time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	problem := Problem{
+		ID:           1,
+		Title:        "Two Sum",
+		TitleSlug:    "two-sum",
+		CodeSnippets: map[string]string{"golang": "func twoSum(nums []int, target int) []int {\n}"},
+		TopicTags:    []TopicTag{{Name: "Array", Slug: "array"}},
+	}
+	assert.NoError(t, c.Put(&problem))
+
+	p, ok := c.Get("two-sum")
+	assert.True(t, ok)
+	assert.Equal(t, problem, p)
+
+	// survives restarts
+	reopened, err := NewDiskCache(filepath.Join(dir, "problems"), time.Hour)
+	assert.NoError(t, err)
+	reopened.now = c.now
+	_, ok = reopened.Get("two-sum")
+	assert.True(t, ok)
+
+	// expired entries are kept for offline use
+	now = now.Add(time.Hour)
+	_, ok = c.Get("two-sum")
+	assert.False(t, ok)
+	e, ok := c.Entry("two-sum")
+	assert.True(t, ok)
+	assert.True(t, e.Stale(now))
+	assert.Equal(t, now.Add(-time.Hour), e.FetchedAt)
+	assert.Equal(t, problem, e.Problem)
+
+	assert.NoError(t, c.Remove("two-sum"))
+	assert.NoError(t, c.Remove("two-sum"))
+	_, ok = c.Entry("two-sum")
+	assert.False(t, ok)
+
+	files, err := os.ReadDir(filepath.Join(dir, "problems"))
+	assert.NoError(t, err)
+	assert.Empty(t, files, "no temp files are left behind")
+}
+
+func TestUnit_DiskCacheEntries(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewDiskCache(dir, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := map[string]struct {
+		titleSlug string
+		content   string // written to the entry file as is
+		ok        bool
+	}{
+		"never expires": {
+			titleSlug: "two-sum",
+			ok:        true,
+		},
+		"path separators stay in the cache dir": {
+			titleSlug: "../two-sum",
+			ok:        true,
+		},
+		"dots stay in the cache dir": {
+			titleSlug: "..",
+			ok:        true,
+		},
+		"corrupted entry": {
+			titleSlug: "corrupted",
+			content:   `{"version":1,"problem":`,
+		},
+		"entry of another version": {
+			titleSlug: "old",
+			content:   `{"version":0,"problem":{"TitleSlug":"old"}}`,
+		},
+		"entry of another problem": {
+			titleSlug: "other",
+			content:   `{"version":1,"problem":{"TitleSlug":"two-sum"}}`,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path, ok := c.path(test.titleSlug)
+			assert.True(t, ok)
+			assert.Equal(t, dir, filepath.Dir(path))
+
+			if test.content == "" {
+				assert.NoError(t, c.Put(&Problem{TitleSlug: test.titleSlug}))
+			} else {
+				assert.NoError(t, os.WriteFile(path, []byte(test.content), 0o600))
+			}
+
+			p, ok := c.Get(test.titleSlug)
+			assert.Equal(t, test.ok, ok)
+			if test.ok {
+				assert.Equal(t, test.titleSlug, p.TitleSlug)
+				return
+			}
+			_, err := os.Stat(path)
+			assert.ErrorIs(t, err, os.ErrNotExist, "invalid entries are removed")
+		})
+	}
+
+	assert.Error(t, c.Put(&Problem{}))
+	_, ok := c.Get("")
+	assert.False(t, ok)
+}
+
+func TestUnit_DiskCacheOfflineData(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir(), time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, ok := c.dailyProblem("2024-01-01")
+	assert.False(t, ok)
+	_, ok = c.storedProblemIndex()
+	assert.False(t, ok)
+
+	assert.NoError(t, c.setDailyProblem("2024-01-01", "two-sum"))
+	assert.NoError(t, c.setDailyProblem("2024-01-02", "add-two-numbers"))
+	titleSlug, ok := c.dailyProblem("2024-01-01")
+	assert.True(t, ok)
+	assert.Equal(t, "two-sum", titleSlug)
+	titleSlug, ok = c.dailyProblem("2024-01-02")
+	assert.True(t, ok)
+	assert.Equal(t, "add-two-numbers", titleSlug)
+
+	refs := []problemTitleMap{{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"}}
+	assert.NoError(t, c.setProblemIndex(refs))
+	stored, ok := c.storedProblemIndex()
+	assert.True(t, ok)
+	assert.Equal(t, refs, stored)
+
+	// meta files are not problems
+	_, ok = c.Entry(diskCacheMetaDir)
+	assert.False(t, ok)
+
+	// corrupt history is started over
+	assert.NoError(t, os.WriteFile(filepath.Join(c.dir, diskCacheMetaDir, diskCacheDailyFile), []byte("{"), 0o600))
+	_, ok = c.dailyProblem("2024-01-01")
+	assert.False(t, ok)
+	assert.NoError(t, c.setDailyProblem("2024-01-03", "two-sum"))
+	_, ok = c.dailyProblem("2024-01-03")
+	assert.True(t, ok)
+
+	// history written before problem files changed their version is kept
+	history := []byte(`{"version":1,"daily":{"2023-12-31":"two-sum"}}`)
+	assert.NoError(t, os.WriteFile(filepath.Join(c.dir, diskCacheMetaDir, diskCacheDailyFile), history, 0o600))
+	titleSlug, ok = c.dailyProblem("2023-12-31")
+	assert.True(t, ok)
+	assert.Equal(t, "two-sum", titleSlug)
+}
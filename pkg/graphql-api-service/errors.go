@@ -0,0 +1,112 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	maxErrorBodyLength = 256
+)
+
+type (
+	// GraphQLError is a single entry of the errors array of a GraphQL response.
+	GraphQLError struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path,omitempty"`
+		Extensions map[string]interface{} `json:"extensions,omitempty"`
+	}
+
+	// HTTPError is returned when the API responds with a non-successful status code
+	// and no GraphQL errors in the body.
+	HTTPError struct {
+		StatusCode int
+		Body       string        // truncated response body
+		RetryAfter time.Duration // parsed Retry-After header, zero if absent
+	}
+)
+
+func (e *GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("graphql: %s", e.Message)
+	}
+
+	path := make([]string, 0, len(e.Path))
+	for _, p := range e.Path {
+		path = append(path, fmt.Sprint(p))
+	}
+	return fmt.Sprintf("graphql: %s (path: %s)", e.Message, strings.Join(path, "."))
+}
+
+// Is maps known GraphQL error messages and codes to package sentinel errors.
+func (e *GraphQLError) Is(target error) bool {
+	message := strings.ToLower(e.Message)
+	code, _ := e.Extensions["code"].(string)
+
+	switch target {
+	case ErrorProblemNotFound:
+		return strings.Contains(message, "not found") || strings.Contains(message, "does not exist")
+	case ErrorUnauthorized:
+		return code == "UNAUTHENTICATED" || code == "FORBIDDEN" ||
+			strings.Contains(message, "not authorized") || strings.Contains(message, "login required")
+	case ErrorRateLimited:
+		return code == "RATE_LIMITED" || strings.Contains(message, "too many requests")
+	}
+	return false
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("http status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("http status %d: %s", e.StatusCode, e.Body)
+}
+
+// Is maps response status codes to package sentinel errors.
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrorProblemNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrorUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrorRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrorUnavailable:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+func newHTTPError(response *http.Response, body []byte) *HTTPError {
+	text := strings.TrimSpace(string(body))
+	if len(text) > maxErrorBodyLength {
+		text = text[:maxErrorBodyLength] + "..."
+	}
+	return &HTTPError{
+		StatusCode: response.StatusCode,
+		Body:       text,
+		RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// wrapAPIError keeps errors callers can act on and reports everything else as ErrorSystem.
+func wrapAPIError(err error, message string) error {
+	for _, target := range []error{
+		ErrorProblemNotFound,
+		ErrorUnauthorized,
+		ErrorRateLimited,
+		ErrorUnavailable,
+		ErrorOffline,
+		context.Canceled,
+		context.DeadlineExceeded,
+	} {
+		if errors.Is(err, target) {
+			return fmt.Errorf("%s: %w", message, err)
+		}
+	}
+	return fmt.Errorf("%w: %s: %v", ErrorSystem, message, err)
+}
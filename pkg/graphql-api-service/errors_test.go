@@ -0,0 +1,114 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_DoRequestErrors(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+
+	testCases := map[string]struct {
+		status  int
+		body    string
+		target  error
+		message string
+	}{
+		"graphql error": {
+			status:  http.StatusOK,
+			body:    `{"data":null,"errors":[{"message":"That question does not exist!","path":["question",0]}]}`,
+			target:  ErrorProblemNotFound,
+			message: "graphql: That question does not exist! (path: question.0)",
+		},
+		"graphql validation error": {
+			status:  http.StatusBadRequest,
+			body:    `{"errors":[{"message":"Cannot query field \"foo\"","extensions":{"code":"GRAPHQL_VALIDATION_FAILED"}}]}`,
+			target:  nil,
+			message: `graphql: Cannot query field "foo"`,
+		},
+		"graphql unauthenticated": {
+			status:  http.StatusOK,
+			body:    `{"errors":[{"message":"User is not logged in","extensions":{"code":"UNAUTHENTICATED"}}]}`,
+			target:  ErrorUnauthorized,
+			message: "graphql: User is not logged in",
+		},
+		"forbidden": {
+			status:  http.StatusForbidden,
+			body:    "<html>CSRF verification failed</html>",
+			target:  ErrorUnauthorized,
+			message: "http status 403: <html>CSRF verification failed</html>",
+		},
+		"too many requests": {
+			status:  http.StatusTooManyRequests,
+			body:    "",
+			target:  ErrorRateLimited,
+			message: "http status 429",
+		},
+		"bad gateway": {
+			status:  http.StatusBadGateway,
+			body:    "",
+			target:  ErrorUnavailable,
+			message: "http status 502",
+		},
+		"bad request without graphql errors": {
+			status:  http.StatusBadRequest,
+			body:    "bad request",
+			target:  nil,
+			message: "http status 400: bad request",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(test.status, test.body), nil)
+
+			data, err := s.api.doRequest(&http.Request{})
+			assert.Nil(t, data)
+			assert.EqualError(t, err, test.message)
+			if test.target != nil {
+				assert.ErrorIs(t, err, test.target)
+			}
+			for _, other := range []error{ErrorProblemNotFound, ErrorUnauthorized, ErrorRateLimited, ErrorUnavailable} {
+				if other != test.target {
+					assert.False(t, errors.Is(err, other), "unexpected match with %s", other)
+				}
+			}
+		})
+	}
+}
+
+func TestUnit_WrapAPIError(t *testing.T) {
+	err := wrapAPIError(&HTTPError{StatusCode: http.StatusTooManyRequests}, "query")
+	assert.ErrorIs(t, err, ErrorRateLimited)
+	assert.NotErrorIs(t, err, ErrorSystem)
+
+	err = wrapAPIError(fmt.Errorf("wrapped: %w", context.DeadlineExceeded), "query")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = wrapAPIError(fmt.Errorf("%w: network requests are disabled", ErrorOffline), "init request")
+	assert.ErrorIs(t, err, ErrorOffline)
+	assert.NotErrorIs(t, err, ErrorSystem)
+
+	err = wrapAPIError(fmt.Errorf("response unmarshal"), "query")
+	assert.ErrorIs(t, err, ErrorSystem)
+	assert.EqualError(t, err, "system error: query: response unmarshal")
+}
+
+func TestUnit_GetProblemByTitleSlugNotFound(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK, `{"data":{"questionData":null}}`), nil)
+
+	_, err := s.api.GetProblemByTitleSlug("unknown-problem")
+	assert.ErrorIs(t, err, ErrorProblemNotFound)
+	assert.NotErrorIs(t, err, ErrorSystem)
+}
@@ -0,0 +1,151 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	flightKeyDailyTitle    = "daily-title"
+	flightKeyProblemPrefix = "problem/"
+)
+
+var errFlightAborted = errors.New("in-flight request aborted")
+
+type (
+	// flightGroup collapses concurrent calls with the same key into a single one whose result
+	// is shared by all callers. Zero value is ready to use.
+	flightGroup struct {
+		mu    sync.Mutex
+		calls map[string]*flightCall
+	}
+
+	flightCall struct {
+		done chan struct{}
+		dups int // callers waiting for the result, for tests
+
+		val interface{}
+		err error
+	}
+)
+
+// do runs fn unless a call with the same key is in flight, in which case it waits for that call's result.
+// fn runs with the context of the first caller, if that caller gives up while others still wait,
+// one of them repeats the call with its own context.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	for {
+		g.mu.Lock()
+		if g.calls == nil {
+			g.calls = make(map[string]*flightCall)
+		}
+		call, ok := g.calls[key]
+		if !ok {
+			call = &flightCall{done: make(chan struct{})}
+			g.calls[key] = call
+			g.mu.Unlock()
+
+			g.run(key, call, func() (interface{}, error) {
+				return fn(ctx)
+			})
+			return call.val, call.err
+		}
+		call.dups++
+		g.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for in-flight request: %w", ctx.Err())
+		}
+
+		leaderGaveUp := errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded)
+		if leaderGaveUp && ctx.Err() == nil {
+			continue
+		}
+		return call.val, call.err
+	}
+}
+
+func (g *flightGroup) run(key string, call *flightCall, fn func() (interface{}, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	// waiters see an error rather than a nil result if fn panics
+	call.err = errFlightAborted
+	call.val, call.err = fn()
+}
+
+// fetchProblem requests the problem once for all concurrent callers and caches it.
+func (c *Client) fetchProblem(ctx context.Context, titleSlug string) (Problem, error) {
+	v, err := c.flights.do(ctx, flightKeyProblemPrefix+titleSlug, func(ctx context.Context) (interface{}, error) {
+		// the previous flight may have finished right after the caller missed the cache
+		if p, cacheHit := c.problemCache.Get(titleSlug); cacheHit {
+			return p, nil
+		}
+
+		data, err := c.getProblemDataByTitleSlug(ctx, titleSlug)
+		if err != nil {
+			return nil, wrapAPIError(err, "get problem data from API")
+		}
+
+		problem, err := externalProblemFromProblemData(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrorSystem, err)
+		}
+		c.problemCache.Add(&problem)
+
+		return problem, nil
+	})
+	if err != nil {
+		return Problem{}, err
+	}
+	return v.(Problem), nil
+}
+
+// staleProblem returns the problem from caches that keep it past its expiration.
+func (c *Client) staleProblem(titleSlug string) (Problem, bool) {
+	s, ok := c.problemCache.(staleCache)
+	if !ok {
+		return Problem{}, false
+	}
+	e, ok := s.Entry(titleSlug)
+	return e.Problem, ok
+}
+
+// revalidate fetches the problem again in the background, concurrent revalidations share a single request.
+// Nothing is fetched once the client is stopped.
+func (c *Client) revalidate(titleSlug string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.background.Err() != nil {
+		return
+	}
+
+	c.revalidations.Add(1)
+	go func() {
+		defer c.revalidations.Done()
+
+		ctx, cancel := context.WithTimeout(c.background, c.timeout)
+		defer cancel()
+		if _, err := c.fetchProblem(ctx, titleSlug); err != nil {
+			c.logger.Printf("Error revalidating problem %s: %s", titleSlug, err)
+		}
+	}()
+}
+
+// fetchDailyProblemTitle requests the daily problem title once for all concurrent callers.
+func (c *Client) fetchDailyProblemTitle(ctx context.Context) (string, error) {
+	v, err := c.flights.do(ctx, flightKeyDailyTitle, func(ctx context.Context) (interface{}, error) {
+		return c.getDailyProblemTitle(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
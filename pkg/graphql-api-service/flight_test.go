@@ -0,0 +1,238 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n callers wait for the in-flight call with the key.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[key]
+		dups := 0
+		if ok {
+			dups = call.dups
+		}
+		g.mu.Unlock()
+		if dups >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d callers did not join the %s call", n, key)
+}
+
+// waitForCall blocks until a call with the key is in flight.
+func waitForCall(t *testing.T, g *flightGroup, key string) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		_, ok := g.calls[key]
+		g.mu.Unlock()
+		if ok {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%s call did not start", key)
+}
+
+func TestUnit_FlightGroup(t *testing.T) {
+	t.Run("concurrent callers share the result", func(t *testing.T) {
+		g := &flightGroup{}
+		release := make(chan struct{})
+		var calls int32
+
+		var wg sync.WaitGroup
+		results := make([]interface{}, 10)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				v, err := g.do(context.Background(), "key", func(context.Context) (interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+					<-release
+					return "value", nil
+				})
+				assert.NoError(t, err)
+				results[i] = v
+			}(i)
+		}
+		waitForWaiters(t, g, "key", len(results)-1)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		for _, v := range results {
+			assert.Equal(t, "value", v)
+		}
+		assert.Empty(t, g.calls)
+	})
+
+	t.Run("waiter repeats the call when the first caller gives up", func(t *testing.T) {
+		g := &flightGroup{}
+		leaderCtx, cancel := context.WithCancel(context.Background())
+		var calls int32
+
+		fn := func(ctx context.Context) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return "value", nil
+		}
+
+		leaderDone := make(chan error)
+		go func() {
+			_, err := g.do(leaderCtx, "key", fn)
+			leaderDone <- err
+		}()
+		waitForCall(t, g, "key")
+
+		waiterDone := make(chan interface{})
+		go func() {
+			v, err := g.do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+			waiterDone <- v
+		}()
+		waitForWaiters(t, g, "key", 1)
+
+		cancel()
+		assert.ErrorIs(t, <-leaderDone, context.Canceled)
+		assert.Equal(t, "value", <-waiterDone)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("waiter gives up", func(t *testing.T) {
+		g := &flightGroup{}
+		release := make(chan struct{})
+		defer close(release)
+
+		go func() {
+			_, _ = g.do(context.Background(), "key", func(context.Context) (interface{}, error) {
+				<-release
+				return nil, nil
+			})
+		}()
+		waitForCall(t, g, "key")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := g.do(ctx, "key", func(context.Context) (interface{}, error) {
+			t.Error("the call is in flight already")
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestUnit_ConcurrentDailyProblem(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	release := make(chan struct{})
+	gomock.InOrder(
+		s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+			<-release
+			return newJSONResponse(http.StatusOK,
+				`{"data":{"activeDailyCodingChallengeQuestion":{"question":{"titleSlug":"two-sum"}}}}`), nil
+		}).Times(1),
+		s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+			<-release
+			return newJSONResponse(http.StatusOK,
+				`{"data":{"questionData":{"questionId":"1","title":"Two Sum","titleSlug":"two-sum"}}}`), nil
+		}).Times(1),
+	)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := s.api.GetDailyProblemContext(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "two-sum", p.TitleSlug)
+		}()
+	}
+
+	waitForWaiters(t, &s.api.flights, flightKeyDailyTitle, callers-1)
+	close(release)
+	wg.Wait()
+}
+
+func TestUnit_StaleWhileRevalidate(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	cache := NewLRUCache(CacheConfig{TTL: time.Hour, StaleTTL: time.Hour})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	s.api.problemCache = cache
+	cache.Add(&Problem{ID: 1, Title: "Old Title", TitleSlug: "two-sum"})
+	now = now.Add(time.Hour)
+
+	release := make(chan struct{})
+	s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+		<-release
+		return newJSONResponse(http.StatusOK,
+			`{"data":{"questionData":{"questionId":"1","title":"Two Sum","titleSlug":"two-sum"}}}`), nil
+	}).Times(1)
+
+	// stale problems are served without waiting for the request
+	for i := 0; i < 3; i++ {
+		p, err := s.api.GetProblemByTitleSlugContext(context.Background(), "two-sum")
+		assert.NoError(t, err)
+		assert.Equal(t, "Old Title", p.Title)
+	}
+	close(release)
+	s.api.revalidations.Wait()
+
+	p, err := s.api.GetProblemByTitleSlugContext(context.Background(), "two-sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "Two Sum", p.Title)
+	assert.Equal(t, int64(3), cache.Stats().StaleHits)
+}
+
+func TestUnit_StopCancelsRevalidation(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	cache := NewLRUCache(CacheConfig{TTL: time.Hour, StaleTTL: time.Hour})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	s.api.problemCache = cache
+	cache.Add(&Problem{ID: 1, Title: "Old Title", TitleSlug: "two-sum"})
+	now = now.Add(time.Hour)
+
+	started := make(chan struct{})
+	s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}).Times(1)
+
+	_, err := s.api.GetProblemByTitleSlugContext(context.Background(), "two-sum")
+	assert.NoError(t, err)
+	<-started
+	// returns only after the revalidation is cancelled
+	s.api.Stop()
+
+	// no more requests once stopped
+	p, err := s.api.GetProblemByTitleSlugContext(context.Background(), "two-sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "Old Title", p.Title)
+	s.api.revalidations.Wait()
+}
@@ -0,0 +1,111 @@
+package graphqlapiservice
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	// problemIndex is an immutable lookup index built from the full problem list.
+	// It is never modified after construction, so readers only need to grab the
+	// current pointer under the client lock.
+	problemIndex struct {
+		problemIDMap    map[int]string    // frontend id => titleSlug
+		problemTitleMap map[string]string // normalized title => titleSlug
+		problemSlugMap  map[string]problemTitleMap
+	}
+)
+
+func newProblemIndex(refs []problemTitleMap) *problemIndex {
+	idx := &problemIndex{
+		problemIDMap:    make(map[int]string, len(refs)),
+		problemTitleMap: make(map[string]string, len(refs)),
+		problemSlugMap:  make(map[string]problemTitleMap, len(refs)),
+	}
+
+	for _, ref := range refs {
+		if ref.TitleSlug == "" {
+			continue
+		}
+		idx.problemSlugMap[ref.TitleSlug] = ref
+
+		if title := normalizeTitle(ref.Title); title != "" {
+			idx.problemTitleMap[title] = ref.TitleSlug
+		}
+		if title := normalizeTitle(ref.TranslatedTitle); title != "" {
+			if _, ok := idx.problemTitleMap[title]; !ok {
+				idx.problemTitleMap[title] = ref.TitleSlug
+			}
+		}
+
+		// some frontend ids are not numeric (e.g. contest-only problems), those are reachable by title only
+		if id, err := strconv.Atoi(strings.TrimSpace(ref.ID)); err == nil {
+			idx.problemIDMap[id] = ref.TitleSlug
+		}
+	}
+
+	return idx
+}
+
+func (idx *problemIndex) size() int {
+	return len(idx.problemSlugMap)
+}
+
+// refs returns the problems the index was built from, ordered by title slug.
+func (idx *problemIndex) refs() []problemTitleMap {
+	refs := make([]problemTitleMap, 0, len(idx.problemSlugMap))
+	for _, ref := range idx.problemSlugMap {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].TitleSlug < refs[j].TitleSlug
+	})
+	return refs
+}
+
+func (idx *problemIndex) titleSlugByID(id int) (string, bool) {
+	titleSlug, ok := idx.problemIDMap[id]
+	return titleSlug, ok
+}
+
+// titleSlugByTitle accepts either a problem title or a title slug in any case and punctuation.
+func (idx *problemIndex) titleSlugByTitle(title string) (string, bool) {
+	if _, ok := idx.problemSlugMap[title]; ok {
+		return title, true
+	}
+	titleSlug, ok := idx.problemTitleMap[normalizeTitle(title)]
+	return titleSlug, ok
+}
+
+func (idx *problemIndex) reference(titleSlug string) (problemTitleMap, bool) {
+	ref, ok := idx.problemSlugMap[titleSlug]
+	return ref, ok
+}
+
+// normalizeTitle lowercases the title, drops apostrophes and turns any other
+// punctuation or whitespace run into a single space, so that "Pow(x, n)",
+// "pow(x,n)" and "pow-x-n" all map to "pow x n".
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	b.Grow(len(title))
+
+	pendingSpace := false
+	for _, r := range title {
+		switch {
+		case r == '\'' || r == '’':
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if pendingSpace && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			pendingSpace = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			pendingSpace = true
+		}
+	}
+
+	return b.String()
+}
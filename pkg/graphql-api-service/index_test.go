@@ -0,0 +1,114 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_NormalizeTitle(t *testing.T) {
+	testCases := map[string]string{
+		"Two Sum":                       "two sum",
+		"  two   SUM ":                  "two sum",
+		"two-sum":                       "two sum",
+		"Pow(x, n)":                     "pow x n",
+		"pow(x,n)":                      "pow x n",
+		"Pascal's Triangle":             "pascals triangle",
+		"Pascal’s Triangle":             "pascals triangle",
+		"3Sum":                          "3sum",
+		"Add Two Numbers II":            "add two numbers ii",
+		"Best Time to Buy and Sell...!": "best time to buy and sell",
+		"":                              "",
+	}
+
+	for title, expected := range testCases {
+		t.Run(title, func(t *testing.T) {
+			assert.Equal(t, expected, normalizeTitle(title))
+		})
+	}
+}
+
+func TestUnit_ProblemIndex(t *testing.T) {
+	idx := newProblemIndex([]problemTitleMap{
+		{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"},
+		{Title: "Pow(x, n)", TitleSlug: "powx-n", ID: "50"},
+		{Title: "Contest Only", TitleSlug: "contest-only", ID: "LCP 01"},
+		{Title: "No Slug", TitleSlug: "", ID: "2"},
+	})
+	assert.Equal(t, 3, idx.size())
+
+	titleSlug, ok := idx.titleSlugByID(1)
+	assert.True(t, ok)
+	assert.Equal(t, "two-sum", titleSlug)
+
+	_, ok = idx.titleSlugByID(2)
+	assert.False(t, ok)
+
+	testCases := map[string]struct {
+		title     string
+		titleSlug string
+		ok        bool
+	}{
+		"exact title":     {title: "Two Sum", titleSlug: "two-sum", ok: true},
+		"different case":  {title: "two sum", titleSlug: "two-sum", ok: true},
+		"punctuation":     {title: "pow(x,n)", titleSlug: "powx-n", ok: true},
+		"title slug":      {title: "powx-n", titleSlug: "powx-n", ok: true},
+		"non numeric id":  {title: "contest only", titleSlug: "contest-only", ok: true},
+		"unknown problem": {title: "Three Sum", titleSlug: "", ok: false},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			titleSlug, ok := idx.titleSlugByTitle(test.title)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.titleSlug, titleSlug)
+		})
+	}
+
+	ref, ok := idx.reference("powx-n")
+	assert.True(t, ok)
+	assert.Equal(t, "50", ref.ID)
+}
+
+func TestUnit_RefreshTitleSlugMaps(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	gomock.InOrder(
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"problemsetQuestionList":{"total":2}}}`,
+		), nil),
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"problemsetQuestionList":{"questions":[`+
+				`{"title":"Two Sum","titleSlug":"two-sum","frontendQuestionId":"1"},`+
+				`{"title":"Add Two Numbers","titleSlug":"add-two-numbers","frontendQuestionId":"2"}]}}}`,
+		), nil),
+		// index is up to date, only the count is requested
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"problemsetQuestionList":{"total":2}}}`,
+		), nil),
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(nil, fmt.Errorf("test error")),
+	)
+
+	err := s.api.refreshTitleSlugMaps(context.Background())
+	assert.NoError(t, err)
+	idx := s.api.index()
+	assert.Equal(t, 2, idx.size())
+	titleSlug, ok := idx.titleSlugByID(2)
+	assert.True(t, ok)
+	assert.Equal(t, "add-two-numbers", titleSlug)
+
+	err = s.api.refreshTitleSlugMaps(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, idx, s.api.index())
+
+	// failed refresh keeps the previous index
+	err = s.api.refreshTitleSlugMaps(context.Background())
+	assert.Error(t, err)
+	assert.Same(t, idx, s.api.index())
+}
@@ -0,0 +1,55 @@
+// Command querygen validates .graphql query documents against the schema snapshot and writes
+// Go constants and response types for them.
+//
+//	querygen -schema graphql/schema.json -out queries_gen.go graphql
+//
+// The schema snapshot keeps only the types reachable from the queries of the client. When a query
+// needs a new field, copy its definition from a fresh introspection result of the site.
+// The snapshot is of leetcode.com, leetcode.cn queries are hand-written and not validated.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/gqlgen"
+)
+
+func main() {
+	schemaPath := flag.String("schema", "schema.json", "introspection result of the schema")
+	out := flag.String("out", "queries_gen.go", "output file")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the output file")
+	flag.Parse()
+
+	if flag.NArg() != 1 || *pkg == "" {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: querygen [flags] <query dir>\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if err := run(*schemaPath, flag.Arg(0), *pkg, *out); err != nil {
+		log.Fatalf("querygen: %v", err)
+	}
+}
+
+func run(schemaPath, queryDir, pkg, out string) error {
+	schema, err := gqlgen.LoadSchemaFile(schemaPath)
+	if err != nil {
+		return err
+	}
+	queries, err := gqlgen.LoadQueries(queryDir)
+	if err != nil {
+		return err
+	}
+	if len(queries) == 0 {
+		return fmt.Errorf("no queries in %s", queryDir)
+	}
+
+	src, err := gqlgen.Generate(schema, pkg, queries)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644) //nolint:gosec // generated source is not a secret
+}
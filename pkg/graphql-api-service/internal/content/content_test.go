@@ -0,0 +1,160 @@
+package content
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const goldenTextWidth = 80
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestUnit_Golden renders statements in testdata/*.html and compares them with the .md and .txt files next to them.
+func TestUnit_Golden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.html"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, fixtures)
+
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(fixture, ".html")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			html, err := os.ReadFile(fixture) //nolint:gosec // test fixtures
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for ext, render := range map[string]func(string) string{
+				".md":  Markdown,
+				".txt": func(s string) string { return Text(s, goldenTextWidth) },
+			} {
+				actual := render(string(html))
+				golden := name + ext
+				if *update {
+					assert.NoError(t, os.WriteFile(golden, []byte(actual), 0o600))
+					continue
+				}
+				expected, err := os.ReadFile(golden) //nolint:gosec // test fixtures
+				if assert.NoError(t, err) {
+					assert.Equal(t, string(expected), actual, golden)
+				}
+			}
+		})
+	}
+}
+
+func TestUnit_Markdown(t *testing.T) {
+	testCases := map[string]struct {
+		html     string
+		expected string
+	}{
+		"empty": {
+			html:     "<p>&nbsp;</p>",
+			expected: "",
+		},
+		"entities and whitespace": {
+			html:     "<p>a &lt;= b\n\t&amp;&amp;  c &#39;d&#39; &quot;e&quot;</p>",
+			expected: "a <= b && c 'd' \"e\"\n",
+		},
+		"markdown characters are escaped": {
+			html:     "<p>a * b_c `d`</p>",
+			expected: "a \\* b\\_c \\`d\\`\n",
+		},
+		"emphasis keeps spaces outside": {
+			html:     "<p>a<strong> bold </strong>b <em>italic</em></p>",
+			expected: "a **bold** b *italic*\n",
+		},
+		"code with backticks": {
+			html:     "<p><code>a`b</code></p>",
+			expected: "`` a`b ``\n",
+		},
+		"superscripts and subscripts": {
+			html:     "<p>x<sup>n+1</sup> and a<sub>i</sub> <code>10<sup>-9</sup></code></p>",
+			expected: "x^(n+1) and a\\_i `10^-9`\n",
+		},
+		"line breaks": {
+			html:     "<p>line 1<br>line 2<br /></p>",
+			expected: "line 1\\\nline 2\n",
+		},
+		"nested lists": {
+			html:     "<ol start=\"3\"><li>first<ul><li>nested</li></ul></li><li>second</ol>",
+			expected: "3. first\n   - nested\n4. second\n",
+		},
+		"table without header cells": {
+			html:     "<table><tr><td>a|b</td><td>c</td></tr><tr><td>d</td></tr></table>",
+			expected: "| a\\|b | c   |\n| ---- | --- |\n| d    |     |\n",
+		},
+		"link and image": {
+			html:     `<p><a href="https://leetcode.com">site</a> <img src="a.png" alt="tree"></p>`,
+			expected: "[site](https://leetcode.com) ![tree](a.png)\n",
+		},
+		"headings and rules": {
+			html:     "<h3>Title</h3><hr><blockquote><p>quote</p><p>more</p></blockquote>",
+			expected: "### Title\n\n---\n\n> quote\n>\n> more\n",
+		},
+		"malformed html": {
+			html:     "<p>unclosed <strong>bold<p>next</div> 1 < 2 <!-- comment -->",
+			expected: "unclosed **bold**\n\nnext 1 < 2\n",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Markdown(test.html))
+		})
+	}
+}
+
+func TestUnit_Text(t *testing.T) {
+	testCases := map[string]struct {
+		html     string
+		width    int
+		expected string
+	}{
+		"wrapping": {
+			html:     "<p>the quick brown fox jumps over the lazy dog</p>",
+			width:    15,
+			expected: "the quick brown\nfox jumps over\nthe lazy dog\n",
+		},
+		"no wrapping": {
+			html:     "<p>the quick brown fox</p>",
+			width:    0,
+			expected: "the quick brown fox\n",
+		},
+		"long words are kept whole": {
+			html:     "<p>a verylongword b</p>",
+			width:    5,
+			expected: "a\nverylongword\nb\n",
+		},
+		"lists wrap within their indentation": {
+			html:     "<ul><li>one two three four</li></ul>",
+			width:    12,
+			expected: "- one two\n  three four\n",
+		},
+		"code blocks are indented and never wrapped": {
+			html:     "<pre>\n<strong>Input:</strong> nums = [1,2,3]\n</pre>",
+			width:    10,
+			expected: "    Input: nums = [1,2,3]\n",
+		},
+		"formatting is dropped": {
+			html:     "<p><strong>a</strong> <em>b</em> <code>c</code> 10<sup>4</sup> <img src=\"x.png\"></p>",
+			expected: "a b c 10^4 [image]\n",
+		},
+		"table": {
+			html:     "<table><tr><th>Symbol</th><th>Value</th></tr><tr><td>I</td><td>1</td></tr></table>",
+			expected: "Symbol | Value\n-------+------\nI      | 1\n",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Text(test.html, test.width))
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Package content renders problem statements, which LeetCode serves as HTML, to Markdown and plain text.
+package content
+
+import (
+	"html"
+	"strings"
+)
+
+type (
+	// node is either an element with children or a text node with an empty tag.
+	node struct {
+		tag      string
+		attrs    map[string]string
+		text     string // unescaped
+		children []*node
+	}
+
+	tokenKind int
+
+	token struct {
+		kind        tokenKind
+		tag         string // lowercase
+		attrs       map[string]string
+		text        string // unescaped
+		selfClosing bool
+	}
+)
+
+const (
+	tokenText tokenKind = iota
+	tokenStartTag
+	tokenEndTag
+)
+
+// voidElements never have children nor end tags.
+var voidElements = map[string]bool{
+	"area": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "wbr": true,
+}
+
+// blockElements close an open paragraph, as browsers do.
+var blockElements = map[string]bool{
+	"blockquote": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+}
+
+// parse builds a tree from the HTML fragment. It is lenient like browsers are: unknown end tags are dropped,
+// elements left open are closed at the end and a few end tags that statements tend to omit are implied.
+func parse(s string) *node {
+	root := &node{tag: "#root"}
+	stack := []*node{root}
+	top := func() *node { return stack[len(stack)-1] }
+	closeTag := func(tag string) {
+		for i := len(stack) - 1; i > 0; i-- {
+			if stack[i].tag == tag {
+				stack = stack[:i]
+				return
+			}
+		}
+	}
+
+	for _, t := range tokenize(s) {
+		switch t.kind {
+		case tokenText:
+			top().children = append(top().children, &node{text: t.text})
+
+		case tokenStartTag:
+			if blockElements[t.tag] {
+				closeParagraph(&stack)
+			}
+			switch t.tag {
+			case "li":
+				closeOpen(&stack, "li", "ul", "ol")
+			case "td", "th":
+				closeOpen(&stack, "td", "tr", "table")
+				closeOpen(&stack, "th", "tr", "table")
+			case "tr":
+				closeOpen(&stack, "tr", "table")
+			}
+
+			n := &node{tag: t.tag, attrs: t.attrs}
+			top().children = append(top().children, n)
+			if !voidElements[t.tag] && !t.selfClosing {
+				stack = append(stack, n)
+			}
+
+		case tokenEndTag:
+			closeTag(t.tag)
+		}
+	}
+
+	return root
+}
+
+// closeParagraph closes the paragraph the element is in along with inline elements open inside it.
+func closeParagraph(stack *[]*node) {
+	s := *stack
+	for i := len(s) - 1; i > 0; i-- {
+		switch tag := s[i].tag; {
+		case tag == "p":
+			*stack = s[:i]
+			return
+		case blockElements[tag] || tag == "li" || tag == "td" || tag == "th":
+			return
+		}
+	}
+}
+
+// closeOpen closes the tag if it is open within the innermost of the scope elements.
+func closeOpen(stack *[]*node, tag string, scopes ...string) {
+	s := *stack
+	for i := len(s) - 1; i > 0; i-- {
+		if s[i].tag == tag {
+			*stack = s[:i]
+			return
+		}
+		for _, scope := range scopes {
+			if s[i].tag == scope {
+				return
+			}
+		}
+	}
+}
+
+// tokenize splits HTML into text and tags, comments and declarations are skipped.
+func tokenize(s string) []token {
+	var tokens []token
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{kind: tokenText, text: html.UnescapeString(text.String())})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			next := strings.IndexByte(s[i:], '<')
+			if next < 0 {
+				next = len(s) - i
+			}
+			text.WriteString(s[i : i+next])
+			i += next
+			continue
+		}
+
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			flushText()
+			i += skipPast(rest, "-->")
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			flushText()
+			i += skipPast(rest, ">")
+		case strings.HasPrefix(rest, "</") && len(rest) > 2 && isLetter(rest[2]):
+			flushText()
+			name, _ := readName(rest, 2)
+			tokens = append(tokens, token{kind: tokenEndTag, tag: strings.ToLower(name)})
+			i += skipPast(rest, ">")
+		case len(rest) > 1 && isLetter(rest[1]):
+			flushText()
+			t, n := readStartTag(rest)
+			tokens = append(tokens, t)
+			i += n
+		default:
+			// a lone '<' in text
+			text.WriteByte('<')
+			i++
+		}
+	}
+	flushText()
+
+	return tokens
+}
+
+// readStartTag reads the tag at the start of s and returns it with its length.
+func readStartTag(s string) (token, int) {
+	name, i := readName(s, 1)
+	t := token{kind: tokenStartTag, tag: strings.ToLower(name), attrs: map[string]string{}}
+
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == '>':
+			return t, i + 1
+		case c == '/' && strings.HasPrefix(s[i:], "/>"):
+			t.selfClosing = true
+			return t, i + 2
+		case isSpace(c) || c == '/':
+			i++
+		default:
+			start := i
+			for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && !strings.HasPrefix(s[i:], "/>") {
+				i++
+			}
+			key := strings.ToLower(s[start:i])
+			for i < len(s) && isSpace(s[i]) {
+				i++
+			}
+			value := ""
+			if i < len(s) && s[i] == '=' {
+				i++
+				for i < len(s) && isSpace(s[i]) {
+					i++
+				}
+				value, i = readAttrValue(s, i)
+			}
+			if key != "" {
+				t.attrs[key] = html.UnescapeString(value)
+			}
+		}
+	}
+	// unterminated tag at the end of input
+	return t, len(s)
+}
+
+func readAttrValue(s string, i int) (string, int) {
+	if i >= len(s) {
+		return "", i
+	}
+	if q := s[i]; q == '"' || q == '\'' {
+		end := strings.IndexByte(s[i+1:], q)
+		if end < 0 {
+			return s[i+1:], len(s)
+		}
+		return s[i+1 : i+1+end], i + end + 2
+	}
+	start := i
+	for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+		i++
+	}
+	return s[start:i], i
+}
+
+func readName(s string, i int) (string, int) {
+	start := i
+	for i < len(s) && (isLetter(s[i]) || s[i] >= '0' && s[i] <= '9' || s[i] == '-') {
+		i++
+	}
+	return s[start:i], i
+}
+
+// skipPast returns the length of s up to and including the first occurrence of end, or all of s.
+func skipPast(s, end string) int {
+	i := strings.Index(s, end)
+	if i < 0 {
+		return len(s)
+	}
+	return i + len(end)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
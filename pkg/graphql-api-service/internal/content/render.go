@@ -0,0 +1,486 @@
+package content
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	lineBreak = "\n" // marks <br> within inline content, text nodes never keep newlines
+	nbsp      = "\u00a0"
+	minWidth  = 10 // of wrapped text, however deep it is nested
+)
+
+var (
+	simpleScript    = regexp.MustCompile(`^[-+]?[\pL\pN.]+$`)
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`")
+)
+
+type renderer struct {
+	markdown bool
+	width    int // of text, non-positive disables wrapping
+}
+
+// Markdown converts the statement to GitHub flavored Markdown.
+func Markdown(s string) string {
+	r := &renderer{markdown: true}
+	return r.render(s)
+}
+
+// Text converts the statement to plain text wrapped to width columns, non-positive width disables wrapping.
+// Code blocks and tables are never wrapped.
+func Text(s string, width int) string {
+	r := &renderer{width: width}
+	return r.render(s)
+}
+
+func (r *renderer) render(s string) string {
+	blocks := r.blocks(parse(s).children, r.width)
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// blocks renders block elements as they are and groups everything between them into paragraphs.
+func (r *renderer) blocks(nodes []*node, width int) []string {
+	var out []string
+	var inline strings.Builder
+	flush := func() {
+		if p := r.paragraph(inline.String(), width); p != "" {
+			out = append(out, p)
+		}
+		inline.Reset()
+	}
+
+	for _, n := range nodes {
+		if !blockElements[n.tag] {
+			appendInline(&inline, r.inline(n))
+			continue
+		}
+		flush()
+		out = append(out, r.block(n, width)...)
+	}
+	flush()
+
+	return out
+}
+
+func (r *renderer) block(n *node, width int) []string {
+	switch n.tag {
+	case "pre":
+		return nonEmpty(r.pre(n))
+	case "ul", "ol":
+		return nonEmpty(r.list(n, width))
+	case "table":
+		return nonEmpty(r.table(n))
+	case "hr":
+		return []string{"---"}
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if !r.markdown {
+			return nonEmpty(r.paragraph(r.inlineChildren(n), width))
+		}
+		text := strings.ReplaceAll(r.paragraph(r.inlineChildren(n), 0), "\\\n", " ")
+		if text == "" {
+			return nil
+		}
+		level, _ := strconv.Atoi(n.tag[1:])
+		return []string{strings.Repeat("#", level) + " " + text}
+	case "blockquote":
+		prefix := "> "
+		if !r.markdown {
+			prefix = "  "
+		}
+		inner := strings.Join(r.blocks(n.children, narrower(width, len(prefix))), "\n\n")
+		return nonEmpty(indentLines(inner, prefix, prefix))
+	default:
+		return r.blocks(n.children, width)
+	}
+}
+
+func (r *renderer) inline(n *node) string {
+	if n.tag == "" {
+		text := collapseSpaces(n.text)
+		if r.markdown {
+			text = markdownEscaper.Replace(text)
+		}
+		return text
+	}
+
+	switch n.tag {
+	case "br":
+		return lineBreak
+	case "strong", "b":
+		return r.emphasis(r.inlineChildren(n), "**")
+	case "em", "i":
+		return r.emphasis(r.inlineChildren(n), "*")
+	case "code", "kbd", "samp", "tt":
+		return r.code(strings.NewReplacer("\n", " ", "\t", " ").Replace(rawText(n)))
+	case "sup":
+		return r.script("^", collapseSpaces(rawChildren(n)))
+	case "sub":
+		return r.script("_", collapseSpaces(rawChildren(n)))
+	case "img":
+		return r.image(n.attrs["src"], n.attrs["alt"])
+	case "a":
+		text := r.inlineChildren(n)
+		if href := n.attrs["href"]; r.markdown && href != "" && strings.TrimSpace(text) != "" {
+			return "[" + text + "](" + href + ")"
+		}
+		return text
+	case "script", "style":
+		return ""
+	}
+
+	text := r.inlineChildren(n)
+	if blockElements[n.tag] || n.tag == "li" || n.tag == "td" || n.tag == "th" {
+		// block content in inline context, e.g. paragraphs in table cells
+		return " " + text + " "
+	}
+	return text
+}
+
+func (r *renderer) inlineChildren(n *node) string {
+	var b strings.Builder
+	for _, child := range n.children {
+		appendInline(&b, r.inline(child))
+	}
+	return b.String()
+}
+
+// emphasis keeps the surrounding spaces out of the markers, "** a**" is not bold in Markdown.
+func (r *renderer) emphasis(text, marker string) string {
+	core := strings.Trim(text, " ")
+	if !r.markdown || core == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+	return lead + marker + core + marker + trail
+}
+
+func (r *renderer) code(text string) string {
+	if !r.markdown || text == "" {
+		return text
+	}
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+	return "`" + text + "`"
+}
+
+// script renders superscripts and subscripts as 10^4 and x_i, wrapping anything but a single term in parentheses.
+func (r *renderer) script(marker, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	if !simpleScript.MatchString(text) {
+		text = "(" + text + ")"
+	}
+	if r.markdown {
+		return markdownEscaper.Replace(marker + text)
+	}
+	return marker + text
+}
+
+func (r *renderer) image(src, alt string) string {
+	if r.markdown {
+		if src == "" {
+			return alt
+		}
+		return "![" + alt + "](" + src + ")"
+	}
+	if alt == "" {
+		return "[image]"
+	}
+	return "[image: " + alt + "]"
+}
+
+// paragraph trims the inline content and wraps it in text mode.
+func (r *renderer) paragraph(inline string, width int) string {
+	lines := strings.Split(inline, lineBreak)
+	out := lines[:0]
+	for _, line := range lines {
+		out = append(out, strings.Trim(line, " "))
+	}
+	for len(out) > 0 && out[0] == "" {
+		out = out[1:]
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	if r.markdown {
+		return strings.Join(out, "\\\n")
+	}
+	for i, line := range out {
+		out[i] = wrap(line, width)
+	}
+	return strings.Join(out, "\n")
+}
+
+func (r *renderer) pre(n *node) string {
+	text := strings.TrimPrefix(rawText(n), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	text = strings.Join(lines, "\n")
+
+	if !r.markdown {
+		return indentLines(text, "    ", "    ")
+	}
+	fence := "```"
+	if strings.Contains(text, fence) {
+		fence = "~~~"
+	}
+	return fence + "\n" + text + "\n" + fence
+}
+
+func (r *renderer) list(n *node, width int) string {
+	ordered := n.tag == "ol"
+	number := 1
+	if start, err := strconv.Atoi(n.attrs["start"]); err == nil {
+		number = start
+	}
+
+	var items []string
+	for _, li := range n.children {
+		if li.tag == "" && strings.TrimSpace(li.text) == "" {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		children := li.children
+		if li.tag != "li" {
+			// stray content between items
+			children = []*node{li}
+		}
+		body := strings.Join(r.blocks(children, narrower(width, len(marker))), "\n")
+		items = append(items, indentLines(body, marker, strings.Repeat(" ", len(marker))))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func (r *renderer) table(n *node) string {
+	var rows [][]string
+	columns := 0
+	var collect func(n *node)
+	collect = func(n *node) {
+		for _, child := range n.children {
+			switch child.tag {
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			case "tr":
+				var row []string
+				for _, cell := range child.children {
+					if cell.tag == "td" || cell.tag == "th" {
+						text := strings.ReplaceAll(r.paragraph(r.inlineChildren(cell), 0), "\\\n", " ")
+						text = strings.ReplaceAll(text, "\n", " ")
+						if r.markdown {
+							text = strings.ReplaceAll(text, "|", `\|`)
+						}
+						row = append(row, text)
+					}
+				}
+				if len(row) > columns {
+					columns = len(row)
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	collect(n)
+	if columns == 0 {
+		return ""
+	}
+
+	widths := make([]int, columns)
+	if r.markdown {
+		// shortest separator of a column
+		for j := range widths {
+			widths[j] = 3
+		}
+	}
+	for i := range rows {
+		for len(rows[i]) < columns {
+			rows[i] = append(rows[i], "")
+		}
+		for j, cell := range rows[i] {
+			if w := utf8.RuneCountInString(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	separator := make([]string, columns)
+	for i, row := range rows {
+		cells := make([]string, columns)
+		for j, cell := range row {
+			cells[j] = cell + strings.Repeat(" ", widths[j]-utf8.RuneCountInString(cell))
+		}
+		if r.markdown {
+			lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		} else {
+			lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+		}
+
+		if i == 0 {
+			for j, w := range widths {
+				separator[j] = strings.Repeat("-", w)
+			}
+			if r.markdown {
+				lines = append(lines, "| "+strings.Join(separator, " | ")+" |")
+			} else {
+				lines = append(lines, strings.Join(separator, "-+-"))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// rawText returns the text of the node keeping whitespace, as needed by code.
+func rawText(n *node) string {
+	if n.tag == "" {
+		return strings.ReplaceAll(n.text, nbsp, " ")
+	}
+
+	text := rawChildren(n)
+	switch n.tag {
+	case "br":
+		return "\n"
+	case "img":
+		return n.attrs["alt"]
+	case "sup", "sub":
+		marker := "^"
+		if n.tag == "sub" {
+			marker = "_"
+		}
+		if text = strings.TrimSpace(text); text != "" && !simpleScript.MatchString(text) {
+			text = "(" + text + ")"
+		}
+		if text != "" {
+			text = marker + text
+		}
+	}
+	return text
+}
+
+func rawChildren(n *node) string {
+	var b strings.Builder
+	for _, child := range n.children {
+		b.WriteString(rawText(child))
+	}
+	return b.String()
+}
+
+// appendInline drops spaces doubled where inline elements meet.
+func appendInline(b *strings.Builder, s string) {
+	if s == "" {
+		return
+	}
+	if current := b.String(); strings.HasSuffix(current, " ") || strings.HasSuffix(current, lineBreak) {
+		s = strings.TrimLeft(s, " ")
+	}
+	b.WriteString(s)
+}
+
+// collapseSpaces turns whitespace runs into single spaces, as browsers render text.
+func collapseSpaces(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, c := range s {
+		switch c {
+		case ' ', '\t', '\n', '\r', '\f', '\u00a0':
+			space = true
+		default:
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			b.WriteRune(c)
+		}
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// wrap breaks the line at spaces so that it fits width, words longer than width are kept whole.
+func wrap(line string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	var b strings.Builder
+	column := 0
+	for _, word := range strings.Fields(line) {
+		w := utf8.RuneCountInString(word)
+		switch {
+		case column == 0:
+		case column+1+w > width:
+			b.WriteByte('\n')
+			column = 0
+		default:
+			b.WriteByte(' ')
+			column++
+		}
+		b.WriteString(word)
+		column += w
+	}
+	return b.String()
+}
+
+// indentLines prefixes the first line with first and the rest with rest, empty lines are kept empty.
+func indentLines(text, first, rest string) string {
+	if text == "" {
+		return strings.TrimRight(first, " ")
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func narrower(width, by int) int {
+	if width <= 0 {
+		return width
+	}
+	if width-by < minWidth {
+		return minWidth
+	}
+	return width - by
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,444 @@
+// Package gqlgen parses GraphQL query documents, validates them against an introspection
+// snapshot of the schema and generates Go query constants and response types from them.
+// Only the subset of the language used by the client is supported: a single operation
+// per document with variables, aliases, arguments and nested selections.
+package gqlgen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenPunct
+	tokenString
+	tokenNumber
+)
+
+type (
+	tokenKind int
+
+	token struct {
+		kind  tokenKind
+		value string
+		line  int
+	}
+
+	lexer struct {
+		src  string
+		pos  int
+		line int
+	}
+
+	parser struct {
+		lex  *lexer
+		next token
+	}
+
+	// Operation is a parsed query document.
+	Operation struct {
+		Type      string // query, mutation or subscription
+		Name      string
+		Variables []*Variable
+		Selection []*Field
+	}
+
+	Variable struct {
+		Name string
+		Type *TypeRef
+		Line int
+
+		HasDefault bool
+	}
+
+	Field struct {
+		Alias     string // empty if not aliased
+		Name      string
+		Arguments []*Argument
+		Selection []*Field
+		Line      int
+	}
+
+	Argument struct {
+		Name     string
+		Variable string // name of the variable passed, empty for literals
+	}
+)
+
+// Key returns the name of the field in the response.
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// ParseOperation parses a document with exactly one operation.
+func ParseOperation(doc string) (*Operation, error) {
+	p := &parser{lex: &lexer{src: doc, line: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	op, err := p.operation()
+	if err != nil {
+		return nil, err
+	}
+	if p.next.kind != tokenEOF {
+		return nil, p.errorf("unexpected %q after the operation, only one operation per document is supported", p.next.value)
+	}
+	return op, nil
+}
+
+func (l *lexer) token() (token, error) {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		switch {
+		case r == '\n':
+			l.line++
+			l.pos += size
+		case r == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case r == ',' || r == '\uFEFF' || unicode.IsSpace(r):
+			l.pos += size
+		default:
+			return l.scan(r)
+		}
+	}
+	return token{kind: tokenEOF, line: l.line}, nil
+}
+
+func (l *lexer) scan(r rune) (token, error) {
+	start := l.pos
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", line: l.line}, nil
+	case strings.ContainsRune("!$():=@[]{}|", r):
+		l.pos++
+		return token{kind: tokenPunct, value: string(r), line: l.line}, nil
+	case r == '_' || isLetter(r):
+		for l.pos < len(l.src) && isNameChar(rune(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], line: l.line}, nil
+	case r == '-' || isDigit(r):
+		l.pos++
+		for l.pos < len(l.src) && strings.ContainsRune("0123456789.eE+-", rune(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenNumber, value: l.src[start:l.pos], line: l.line}, nil
+	case r == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return token{}, fmt.Errorf("line %d: block strings are not supported", l.line)
+		}
+		l.pos++
+		for l.pos < len(l.src) {
+			switch l.src[l.pos] {
+			case '\\':
+				l.pos += 2
+				continue
+			case '\n':
+				return token{}, fmt.Errorf("line %d: unterminated string", l.line)
+			case '"':
+				l.pos++
+				return token{kind: tokenString, value: l.src[start:l.pos], line: l.line}, nil
+			}
+			l.pos++
+		}
+		return token{}, fmt.Errorf("line %d: unterminated string", l.line)
+	}
+	return token{}, fmt.Errorf("line %d: unexpected character %q", l.line, r)
+}
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isNameChar(r rune) bool {
+	return r == '_' || isLetter(r) || isDigit(r)
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.token()
+	if err != nil {
+		return err
+	}
+	p.next = t
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", p.next.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) peek(value string) bool {
+	return p.next.kind == tokenPunct && p.next.value == value
+}
+
+// skip consumes the punctuator if it is next.
+func (p *parser) skip(value string) (bool, error) {
+	if !p.peek(value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(value string) error {
+	if !p.peek(value) {
+		return p.errorf("expected %q, got %q", value, p.next.value)
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.next.kind != tokenName {
+		return "", p.errorf("expected name, got %q", p.next.value)
+	}
+	name := p.next.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: "query"}
+	if p.next.kind == tokenName {
+		switch p.next.value {
+		case "query", "mutation", "subscription":
+			op.Type = p.next.value
+		case "fragment":
+			return nil, p.errorf("fragments are not supported")
+		default:
+			return nil, p.errorf("unknown operation type %q", p.next.value)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+
+		if p.next.kind == tokenName {
+			op.Name = p.next.value
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if p.peek("(") {
+			vars, err := p.variables()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = vars
+		}
+	}
+	if p.peek("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	selection, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selection = selection
+	return op, nil
+}
+
+func (p *parser) variables() ([]*Variable, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var vars []*Variable
+	for !p.peek(")") {
+		v := &Variable{Line: p.next.line}
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		v.Name = name
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		if v.Type, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if v.HasDefault, err = p.skip("="); err != nil {
+			return nil, err
+		}
+		if v.HasDefault {
+			if _, err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		vars = append(vars, v)
+	}
+	return vars, p.advance()
+}
+
+func (p *parser) typeRef() (*TypeRef, error) {
+	var ref *TypeRef
+	if ok, err := p.skip("["); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.typeRef()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect("]"); err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Kind: KindList, OfType: elem}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		ref = &TypeRef{Name: name}
+	}
+
+	nonNull, err := p.skip("!")
+	if err != nil {
+		return nil, err
+	}
+	if nonNull {
+		ref = &TypeRef{Kind: KindNonNull, OfType: ref}
+	}
+	return ref, nil
+}
+
+func (p *parser) selectionSet() ([]*Field, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []*Field
+	for !p.peek("}") {
+		if p.next.kind == tokenEOF {
+			return nil, p.errorf("unterminated selection set")
+		}
+		if p.peek("...") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		f, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return fields, p.advance()
+}
+
+func (p *parser) field() (*Field, error) {
+	f := &Field{Line: p.next.line}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	f.Name = name
+
+	aliased, err := p.skip(":")
+	if err != nil {
+		return nil, err
+	}
+	if aliased {
+		f.Alias = f.Name
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek("(") {
+		if f.Arguments, err = p.arguments(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek("@") {
+		return nil, p.errorf("directives are not supported")
+	}
+	if p.peek("{") {
+		if f.Selection, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() ([]*Argument, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []*Argument
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+		variable, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, &Argument{Name: name, Variable: variable})
+	}
+	return args, p.advance()
+}
+
+// value consumes an input value and returns the variable name if the value is a variable.
+// Literals are only checked for syntax.
+func (p *parser) value() (string, error) {
+	switch {
+	case p.peek("$"):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		return p.name()
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		for !p.peek("]") {
+			if p.next.kind == tokenEOF {
+				return "", p.errorf("unterminated list")
+			}
+			if _, err := p.value(); err != nil {
+				return "", err
+			}
+		}
+		return "", p.advance()
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		for !p.peek("}") {
+			if _, err := p.name(); err != nil {
+				return "", err
+			}
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+			if _, err := p.value(); err != nil {
+				return "", err
+			}
+		}
+		return "", p.advance()
+	case p.next.kind == tokenName || p.next.kind == tokenString || p.next.kind == tokenNumber:
+		return "", p.advance()
+	}
+	return "", p.errorf("expected value, got %q", p.next.value)
+}
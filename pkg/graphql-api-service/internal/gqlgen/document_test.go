@@ -0,0 +1,92 @@
+package gqlgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ParseOperation(t *testing.T) {
+	doc := `# comment
+query questionData($titleSlug: String!, $tags: [String!] = ["array"]) {
+	questionData: question(titleSlug: $titleSlug, filters: {tags: $tags, limit: 10}) {
+		title
+		topicTags { slug }
+	}
+}
+`
+	op, err := ParseOperation(doc)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "query", op.Type)
+	assert.Equal(t, "questionData", op.Name)
+	if assert.Len(t, op.Variables, 2) {
+		assert.Equal(t, "String!", op.Variables[0].Type.String())
+		assert.Equal(t, "[String!]", op.Variables[1].Type.String())
+		assert.True(t, op.Variables[1].HasDefault)
+	}
+
+	if assert.Len(t, op.Selection, 1) {
+		f := op.Selection[0]
+		assert.Equal(t, "questionData", f.Key())
+		assert.Equal(t, "question", f.Name)
+		assert.Equal(t, 3, f.Line)
+		assert.Equal(t, []*Argument{{Name: "titleSlug", Variable: "titleSlug"}, {Name: "filters"}}, f.Arguments)
+		assert.Len(t, f.Selection, 2)
+		assert.Equal(t, "slug", f.Selection[1].Selection[0].Name)
+	}
+}
+
+func TestUnit_ParseOperationErrors(t *testing.T) {
+	testCases := map[string]struct {
+		doc string
+		err string
+	}{
+		"anonymous shorthand is fine": {
+			doc: `{ userStatus { username } }`,
+		},
+		"empty document": {
+			doc: ``,
+			err: `line 1: expected "{", got ""`,
+		},
+		"two operations": {
+			doc: "query a { a }\nquery b { b }",
+			err: `line 2: unexpected "query" after the operation`,
+		},
+		"fragment spread": {
+			doc: "{ question { ...fields } }",
+			err: "fragments are not supported",
+		},
+		"unterminated selection": {
+			doc: "{ question { title }",
+			err: "unterminated selection set",
+		},
+		"empty selection": {
+			doc: "{ question { } }",
+			err: "empty selection set",
+		},
+		"unterminated string": {
+			doc: `{ question(titleSlug: "two-sum) { title } }`,
+			err: "unterminated string",
+		},
+		"bad character": {
+			doc: "{ question % }",
+			err: `unexpected character '%'`,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseOperation(test.doc)
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package gqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	queryFileExt = ".graphql"
+
+	querySuffix = "Query"
+	dataSuffix  = "QueryData"
+)
+
+type (
+	// Query is a document loaded from a .graphql file.
+	Query struct {
+		Name     string // Go identifier base derived from the file name, e.g. problemByTitleSlug
+		File     string
+		Document string
+	}
+
+	generator struct {
+		schema  *Schema
+		buf     bytes.Buffer
+		rawJSON bool // custom scalars require encoding/json
+	}
+)
+
+// scalarTypes maps built-in scalars, custom ones are kept as raw JSON.
+var scalarTypes = map[string]string{
+	"String":  "string",
+	"ID":      "string",
+	"Int":     "int",
+	"Float":   "float64",
+	"Boolean": "bool",
+}
+
+// initialisms are upper-cased in Go field names.
+var initialisms = map[string]string{
+	"Id":   "ID",
+	"Url":  "URL",
+	"Html": "HTML",
+	"Json": "JSON",
+	"Api":  "API",
+}
+
+// LoadQueries reads all .graphql files of the directory in name order.
+func LoadQueries(dir string) ([]Query, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+queryFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("list queries: %w", err)
+	}
+	sort.Strings(paths)
+
+	queries := make([]Query, 0, len(paths))
+	for _, path := range paths {
+		doc, err := os.ReadFile(path) //nolint:gosec // files of the query directory
+		if err != nil {
+			return nil, fmt.Errorf("read query: %w", err)
+		}
+		base := strings.TrimSuffix(filepath.Base(path), queryFileExt)
+		queries = append(queries, Query{
+			Name:     identifier(base),
+			File:     filepath.ToSlash(path),
+			Document: string(doc),
+		})
+	}
+	return queries, nil
+}
+
+// identifier turns snake_case file names into unexported camelCase identifiers.
+func identifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for i := range parts {
+		if i > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// exportedName turns a response key into a Go field name, e.g. questionFrontendId => QuestionFrontendID.
+func exportedName(key string) string {
+	key = strings.TrimLeft(key, "_")
+	if key == "" {
+		return "Typename"
+	}
+
+	var words []string
+	start := 0
+	for i, r := range key {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, key[start:i])
+			start = i
+		}
+	}
+	words = append(words, key[start:])
+
+	for i, w := range words {
+		w = strings.ToUpper(w[:1]) + w[1:]
+		if initialism, ok := initialisms[w]; ok {
+			w = initialism
+		}
+		words[i] = w
+	}
+	return strings.Join(words, "")
+}
+
+// Generate validates the queries against the schema and returns formatted Go source with a
+// <name>Query constant holding every document and a <name>QueryData type its data decodes into.
+func Generate(schema *Schema, pkg string, queries []Query) ([]byte, error) {
+	g := &generator{schema: schema}
+
+	ops := make([]*Operation, len(queries))
+	for i, q := range queries {
+		if strings.Contains(q.Document, "`") {
+			return nil, fmt.Errorf("%s: backquotes are not supported", q.File)
+		}
+		op, err := ParseOperation(q.Document)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", q.File, err)
+		}
+		if err = schema.Validate(op); err != nil {
+			return nil, fmt.Errorf("%s: %w", q.File, err)
+		}
+		ops[i] = op
+	}
+
+	g.printf("const (\n")
+	for _, q := range queries {
+		g.printf("// %s%s is generated from %s.\n", q.Name, querySuffix, q.File)
+		g.printf("%s%s = `%s\n`\n", q.Name, querySuffix, strings.TrimSpace(q.Document))
+	}
+	g.printf(")\n\n")
+
+	g.printf("type (\n")
+	for i, q := range queries {
+		root, _ := schema.typeByName(schema.QueryType)
+		if ops[i].Type == "mutation" {
+			root, _ = schema.typeByName(schema.MutationType)
+		}
+		g.printf("%s%s ", q.Name, dataSuffix)
+		g.object(root, ops[i].Selection)
+		g.printf("\n\n")
+	}
+	g.printf(")\n")
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by querygen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	if g.rawJSON {
+		fmt.Fprintf(&out, "import \"encoding/json\"\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) object(t *Type, fields []*Field) {
+	g.printf("struct {\n")
+	for _, f := range fields {
+		g.printf("%s ", exportedName(f.Key()))
+		if f.Name == typenameField {
+			g.printf("string")
+		} else {
+			def, _ := t.field(f.Name)
+			g.typeRef(def.Type, f.Selection, true)
+		}
+		g.printf(" `json:\"%s\"`\n", f.Key())
+	}
+	g.printf("}")
+}
+
+// typeRef prints the Go type of the reference. Nullable objects are pointers unless they are list
+// elements, nullable scalars decode into zero values.
+func (g *generator) typeRef(ref *TypeRef, selection []*Field, nullable bool) {
+	switch ref.Kind {
+	case KindNonNull:
+		g.typeRef(ref.OfType, selection, false)
+		return
+	case KindList:
+		g.printf("[]")
+		g.typeRef(ref.OfType, selection, false)
+		return
+	}
+
+	t, _ := g.schema.typeByName(ref.Name)
+	switch t.Kind {
+	case KindObject, KindInterface:
+		if nullable {
+			g.printf("*")
+		}
+		g.object(t, selection)
+	case KindEnum:
+		g.printf("string")
+	default:
+		if goType, ok := scalarTypes[t.Name]; ok {
+			g.printf("%s", goType)
+		} else {
+			g.rawJSON = true
+			g.printf("json.RawMessage")
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gqlgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_Names(t *testing.T) {
+	assert.Equal(t, "problemByTitleSlug", identifier("problem_by_title_slug"))
+	assert.Equal(t, "QuestionFrontendID", exportedName("questionFrontendId"))
+	assert.Equal(t, "ProfileURL", exportedName("profileUrl"))
+	assert.Equal(t, "Typename", exportedName("__typename"))
+}
+
+func TestUnit_Generate(t *testing.T) {
+	schema, err := LoadSchemaFile(testSchemaPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	src, err := Generate(schema, "test", []Query{{
+		Name:     "test",
+		File:     "test.graphql",
+		Document: "query t($slug: String!) {\n\tq: question(titleSlug: $slug) {\n\t\tquestionId\n\t\thints\n\t\ttopicTags { slug }\n\t}\n}\n",
+	}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "// Code generated by querygen. DO NOT EDIT.\n\n"+
+		"package test\n\n"+
+		"const (\n"+
+		"\t// testQuery is generated from test.graphql.\n"+
+		"\ttestQuery = `query t($slug: String!) {\n\tq: question(titleSlug: $slug) {\n\t\tquestionId\n\t\thints\n\t\ttopicTags { slug }\n\t}\n}\n`\n"+
+		")\n\n"+
+		"type (\n"+
+		"\ttestQueryData struct {\n"+
+		"\t\tQ *struct {\n"+
+		"\t\t\tQuestionID string   `json:\"questionId\"`\n"+
+		"\t\t\tHints      []string `json:\"hints\"`\n"+
+		"\t\t\tTopicTags  []struct {\n"+
+		"\t\t\t\tSlug string `json:\"slug\"`\n"+
+		"\t\t\t} `json:\"topicTags\"`\n"+
+		"\t\t} `json:\"q\"`\n"+
+		"\t}\n"+
+		")\n", string(src))
+
+	_, err = Generate(schema, "test", []Query{{Name: "bad", File: "bad.graphql", Document: "{ question(titleSlug: \"a\") { missing } }"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "bad.graphql: anonymous query: line 1: field missing is not defined on QuestionNode")
+	}
+}
@@ -0,0 +1,310 @@
+package gqlgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+
+	typenameField = "__typename"
+)
+
+type (
+	// Schema is the part of an introspection result needed to validate and generate queries.
+	Schema struct {
+		QueryType    string
+		MutationType string
+		types        map[string]*Type
+	}
+
+	Type struct {
+		Kind        string        `json:"kind"`
+		Name        string        `json:"name"`
+		Fields      []*FieldDef   `json:"fields"`
+		InputFields []*InputValue `json:"inputFields"`
+	}
+
+	FieldDef struct {
+		Name string        `json:"name"`
+		Args []*InputValue `json:"args"`
+		Type *TypeRef      `json:"type"`
+	}
+
+	InputValue struct {
+		Name         string   `json:"name"`
+		Type         *TypeRef `json:"type"`
+		DefaultValue *string  `json:"defaultValue"`
+	}
+
+	// TypeRef is a possibly wrapped reference to a named type. References parsed from
+	// documents have an empty kind for named types until they are resolved against the schema.
+	TypeRef struct {
+		Kind   string   `json:"kind"`
+		Name   string   `json:"name"`
+		OfType *TypeRef `json:"ofType"`
+	}
+
+	// validation collects all problems of a document instead of stopping at the first one.
+	validation struct {
+		schema *Schema
+		vars   map[string]*Variable
+		used   map[string]bool
+		errs   []string
+	}
+
+	introspectionResponse struct {
+		Data struct {
+			Schema struct {
+				QueryType    *struct{ Name string } `json:"queryType"`
+				MutationType *struct{ Name string } `json:"mutationType"`
+				Types        []*Type                `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+)
+
+// String formats the reference the way it is written in documents, e.g. [String!]!.
+func (r *TypeRef) String() string {
+	switch r.Kind {
+	case KindNonNull:
+		return r.OfType.String() + "!"
+	case KindList:
+		return "[" + r.OfType.String() + "]"
+	}
+	return r.Name
+}
+
+// named unwraps lists and non-null wrappers.
+func (r *TypeRef) named() string {
+	for r.OfType != nil {
+		r = r.OfType
+	}
+	return r.Name
+}
+
+// LoadSchemaFile reads the result of the introspection query, see LoadSchema.
+func LoadSchemaFile(path string) (*Schema, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the caller on purpose
+	if err != nil {
+		return nil, fmt.Errorf("open schema: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read only
+
+	s, err := LoadSchema(f)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// LoadSchema reads the JSON result of the standard introspection query.
+func LoadSchema(r io.Reader) (*Schema, error) {
+	resp := &introspectionResponse{}
+	if err := json.NewDecoder(r).Decode(resp); err != nil {
+		return nil, fmt.Errorf("decode introspection: %w", err)
+	}
+
+	raw := resp.Data.Schema
+	if raw.QueryType == nil {
+		return nil, fmt.Errorf("no query type in introspection")
+	}
+
+	s := &Schema{
+		QueryType: raw.QueryType.Name,
+		types:     make(map[string]*Type, len(raw.Types)),
+	}
+	if raw.MutationType != nil {
+		s.MutationType = raw.MutationType.Name
+	}
+	for _, t := range raw.Types {
+		s.types[t.Name] = t
+	}
+	if _, ok := s.types[s.QueryType]; !ok {
+		return nil, fmt.Errorf("query type %s is not defined", s.QueryType)
+	}
+
+	return s, nil
+}
+
+func (s *Schema) typeByName(name string) (*Type, bool) {
+	t, ok := s.types[name]
+	return t, ok
+}
+
+func (t *Type) field(name string) (*FieldDef, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func (f *FieldDef) arg(name string) (*InputValue, bool) {
+	for _, a := range f.Args {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+func (v *validation) errorf(line int, format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, args...)))
+}
+
+// Validate checks that every field, argument and variable of the operation exists in the schema
+// and that argument and variable types agree.
+func (s *Schema) Validate(op *Operation) error {
+	v := &validation{
+		schema: s,
+		vars:   make(map[string]*Variable, len(op.Variables)),
+		used:   make(map[string]bool, len(op.Variables)),
+	}
+
+	for _, variable := range op.Variables {
+		if _, ok := v.vars[variable.Name]; ok {
+			v.errorf(variable.Line, "variable $%s is declared twice", variable.Name)
+		}
+		v.vars[variable.Name] = variable
+
+		t, ok := s.typeByName(variable.Type.named())
+		switch {
+		case !ok:
+			v.errorf(variable.Line, "variable $%s: unknown type %s", variable.Name, variable.Type.named())
+		case t.Kind != KindScalar && t.Kind != KindEnum && t.Kind != KindInputObject:
+			v.errorf(variable.Line, "variable $%s: %s is not an input type", variable.Name, t.Name)
+		}
+	}
+
+	var root string
+	switch op.Type {
+	case "query":
+		root = s.QueryType
+	case "mutation":
+		root = s.MutationType
+	}
+	if rootType, ok := s.typeByName(root); ok {
+		v.selection(rootType, op.Selection)
+	} else {
+		v.errs = append(v.errs, fmt.Sprintf("%s operations are not supported by the schema", op.Type))
+	}
+
+	for _, variable := range op.Variables {
+		if !v.used[variable.Name] {
+			v.errorf(variable.Line, "variable $%s is never used", variable.Name)
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return fmt.Errorf("%s: %s", operationName(op), strings.Join(v.errs, "; "))
+	}
+	return nil
+}
+
+func operationName(op *Operation) string {
+	if op.Name == "" {
+		return "anonymous " + op.Type
+	}
+	return op.Type + " " + op.Name
+}
+
+func (v *validation) selection(parent *Type, fields []*Field) {
+	keys := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		// merging of identical fields is not supported, generated types need unique keys
+		if keys[f.Key()] {
+			v.errorf(f.Line, "%s is selected twice, alias one of the fields", f.Key())
+		}
+		keys[f.Key()] = true
+
+		if f.Name == typenameField {
+			if len(f.Selection) > 0 || len(f.Arguments) > 0 {
+				v.errorf(f.Line, "%s has no arguments or subfields", typenameField)
+			}
+			continue
+		}
+
+		def, ok := parent.field(f.Name)
+		if !ok {
+			v.errorf(f.Line, "field %s is not defined on %s", f.Name, parent.Name)
+			continue
+		}
+		v.arguments(f, def)
+
+		t, ok := v.schema.typeByName(def.Type.named())
+		if !ok {
+			v.errorf(f.Line, "%s.%s: unknown type %s", parent.Name, f.Name, def.Type.named())
+			continue
+		}
+		switch t.Kind {
+		case KindObject, KindInterface:
+			if len(f.Selection) == 0 {
+				v.errorf(f.Line, "%s.%s of type %s requires a selection of subfields", parent.Name, f.Name, t.Name)
+				continue
+			}
+			v.selection(t, f.Selection)
+		case KindUnion:
+			v.errorf(f.Line, "%s.%s: unions are not supported", parent.Name, f.Name)
+		default:
+			if len(f.Selection) > 0 {
+				v.errorf(f.Line, "%s.%s of type %s has no subfields", parent.Name, f.Name, t.Name)
+			}
+		}
+	}
+}
+
+func (v *validation) arguments(f *Field, def *FieldDef) {
+	passed := make(map[string]bool, len(f.Arguments))
+	for _, a := range f.Arguments {
+		passed[a.Name] = true
+
+		argDef, ok := def.arg(a.Name)
+		if !ok {
+			v.errorf(f.Line, "%s has no argument %s", f.Name, a.Name)
+			continue
+		}
+		if a.Variable == "" {
+			continue
+		}
+
+		variable, ok := v.vars[a.Variable]
+		if !ok {
+			v.errorf(f.Line, "variable $%s is not declared", a.Variable)
+			continue
+		}
+		v.used[a.Variable] = true
+		if !assignable(variable, argDef.Type) {
+			v.errorf(f.Line, "variable $%s of type %s can't be passed to %s(%s: %s)",
+				variable.Name, variable.Type, f.Name, argDef.Name, argDef.Type)
+		}
+	}
+
+	for _, argDef := range def.Args {
+		if argDef.Type.Kind == KindNonNull && argDef.DefaultValue == nil && !passed[argDef.Name] {
+			v.errorf(f.Line, "%s requires argument %s", f.Name, argDef.Name)
+		}
+	}
+}
+
+// assignable reports whether the variable matches the argument type, non-null variables may be
+// passed to nullable arguments, as well as nullable variables with a default value to non-null ones.
+func assignable(variable *Variable, argType *TypeRef) bool {
+	varType := variable.Type.String()
+	if varType == argType.String() || varType == argType.String()+"!" {
+		return true
+	}
+	return variable.HasDefault && argType.Kind == KindNonNull && varType == argType.OfType.String()
+}
@@ -0,0 +1,80 @@
+package gqlgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchemaPath = "../../graphql/schema.json"
+
+func TestUnit_Validate(t *testing.T) {
+	schema, err := LoadSchemaFile(testSchemaPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	testCases := map[string]struct {
+		doc  string
+		errs []string
+	}{
+		"valid query": {
+			doc: `query q($slug: String!) { question(titleSlug: $slug) { title topicTags { slug } __typename } }`,
+		},
+		"non-null variable for nullable argument": {
+			doc: `query q($limit: Int!) { questionList(limit: $limit) { totalNum } }`,
+		},
+		"unknown field": {
+			doc:  `query q($slug: String!) { question(titleSlug: $slug) { title nonexistent } }`,
+			errs: []string{"field nonexistent is not defined on QuestionNode"},
+		},
+		"unknown argument": {
+			doc:  `{ questionList(page: 1) { totalNum } }`,
+			errs: []string{"questionList has no argument page"},
+		},
+		"missing required argument": {
+			doc:  `{ question { title } }`,
+			errs: []string{"question requires argument titleSlug"},
+		},
+		"variables": {
+			doc: `query q($slug: String, $unused: Int, $filters: QuestionNode) {
+				question(titleSlug: $slug) { title }
+				questionList(limit: $other) { totalNum }
+			}`,
+			errs: []string{
+				"variable $filters: QuestionNode is not an input type",
+				"variable $slug of type String can't be passed to question(titleSlug: String!)",
+				"variable $other is not declared",
+				"variable $unused is never used",
+			},
+		},
+		"selections": {
+			doc:  `{ userStatus userStatus: activeDailyCodingChallengeQuestion { date { day } } }`,
+			errs: []string{"Query.userStatus of type MeNode requires a selection", "userStatus is selected twice", "DailyChallengeNode.date of type String has no subfields"},
+		},
+		"mutations": {
+			doc:  `mutation m { submit }`,
+			errs: []string{"mutation operations are not supported by the schema"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			op, err := ParseOperation(test.doc)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			err = schema.Validate(op)
+			if len(test.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				for _, msg := range test.errs {
+					assert.Contains(t, err.Error(), msg)
+				}
+			}
+		})
+	}
+}
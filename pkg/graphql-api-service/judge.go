@@ -0,0 +1,150 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPollInterval = 1 * time.Second
+	defaultPollTimeout  = 1 * time.Minute
+
+	checkPathTemplate = "/submissions/detail/%s/check/"
+
+	judgeStatePending = "PENDING"
+	judgeStateStarted = "STARTED"
+)
+
+type (
+	// checkResponse is the judge state returned by the check endpoint for both submissions and code runs.
+	checkResponse struct {
+		State        string `json:"state"`
+		StatusCode   int    `json:"status_code"`
+		StatusMsg    string `json:"status_msg"`
+		SubmissionID string `json:"submission_id"`
+		RunSuccess   bool   `json:"run_success"`
+
+		StatusRuntime     string  `json:"status_runtime"`
+		StatusMemory      string  `json:"status_memory"`
+		RuntimePercentile float64 `json:"runtime_percentile"`
+		MemoryPercentile  float64 `json:"memory_percentile"`
+
+		TotalCorrect   int         `json:"total_correct"`
+		TotalTestcases int         `json:"total_testcases"`
+		LastTestcase   string      `json:"last_testcase"`
+		ExpectedOutput judgeOutput `json:"expected_output"`
+		CodeOutput     judgeOutput `json:"code_output"`
+		StdOutput      judgeOutput `json:"std_output"`
+
+		// code runs only
+		CodeAnswer         []string `json:"code_answer"`
+		ExpectedCodeAnswer []string `json:"expected_code_answer"`
+		StdOutputList      []string `json:"std_output_list"`
+		CorrectAnswer      bool     `json:"correct_answer"`
+		CompareResult      string   `json:"compare_result"` // "1" or "0" per test case
+
+		CompileError     string `json:"compile_error"`
+		FullCompileError string `json:"full_compile_error"`
+		RuntimeError     string `json:"runtime_error"`
+		FullRuntimeError string `json:"full_runtime_error"`
+	}
+
+	// judgeOutput is reported as a string for submissions and as a list of lines for code runs.
+	judgeOutput string
+)
+
+func (o *judgeOutput) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*o = judgeOutput(strings.Join(lines, "\n"))
+		return nil
+	}
+
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("judge output: %w", err)
+	}
+	if s != nil {
+		*o = judgeOutput(*s)
+	}
+	return nil
+}
+
+func (r *checkResponse) finished() bool {
+	return r.State != judgeStatePending && r.State != judgeStateStarted
+}
+
+// judgeQuestion returns internal question id required by judge endpoints, which may differ from the frontend one,
+// along with example test cases.
+func (c *Client) judgeQuestion(ctx context.Context, titleSlug string) (*judgeQuestionQueryData, error) {
+	req, err := c.newRequest(ctx, judgeQuestionQuery, map[string]interface{}{
+		variableTitleSlug: titleSlug,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init request: %w", err)
+	}
+	c.addRefererHeader(req, c.baseURL+fmt.Sprintf(problemRefererTemplate, titleSlug))
+
+	data, err := c.doRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	parsedResponse := &judgeQuestionQueryData{}
+	if err = json.Unmarshal(data, parsedResponse); err != nil {
+		return nil, fmt.Errorf("response unmarshal: %w", err)
+	}
+	if parsedResponse.QuestionData == nil || parsedResponse.QuestionData.QuestionID == "" {
+		return nil, fmt.Errorf("question %s: %w", titleSlug, ErrorProblemNotFound)
+	}
+
+	return parsedResponse, nil
+}
+
+// pollJudge queries the check endpoint until the judge reports a final state or poll timeout expires.
+func (c *Client) pollJudge(ctx context.Context, id, referer string) (*checkResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.pollTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		req, err := c.newRESTRequest(ctx, http.MethodGet, fmt.Sprintf(checkPathTemplate, id), nil)
+		if err != nil {
+			return nil, fmt.Errorf("init request: %w", err)
+		}
+		c.addRefererHeader(req, referer)
+
+		body, err := c.doRESTRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", id, err)
+		}
+
+		result := &checkResponse{}
+		if err = json.Unmarshal(body, result); err != nil {
+			return nil, fmt.Errorf("response unmarshal: %w", err)
+		}
+		if result.finished() {
+			return result, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no judge result for %s (last state %s): %w", id, result.State, ctx.Err())
+		}
+	}
+}
+
+// requireSession fails fast for endpoints that only work for signed-in users.
+func (c *Client) requireSession() error {
+	if c.session == nil {
+		return fmt.Errorf("%w: sign in required, see WithSession", ErrorUnauthorized)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package graphqlapiservice
+
+import "time"
+
+type (
+	// Metrics is a point-in-time snapshot of client counters.
+	Metrics struct {
+		RateLimitWait    time.Duration // total time requests spent waiting for the rate limiter
+		RateLimitedCalls int64         // number of requests that had to wait
+
+		Cache CacheStats // zero unless the cache reports its stats, as LRUCache does
+	}
+)
+
+func (c *Client) Metrics() Metrics {
+	m := Metrics{
+		RateLimitWait:    c.limiter.waitTime(),
+		RateLimitedCalls: c.limiter.waitCount(),
+	}
+	if r, ok := c.problemCache.(cacheStatsReporter); ok {
+		m.Cache = r.Stats()
+	}
+	return m
+}
@@ -1,10 +1,18 @@
 package graphqlapiservice
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strconv"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/content"
 )
 
+// problemVersion is the version of problems serialized by DiskCache and snapshots, bumped whenever Problem
+// changes so that problems missing new fields are requested again rather than served.
+const problemVersion = 2
+
 // TODO: add lang list
 const (
 	LangSlugGolang = "golang"
@@ -12,26 +20,45 @@ const (
 
 type (
 	LeetCodeAPIClient interface {
-		GetProblemDataByTitle(title string) (Problem, error)
-		GetProblemDataByID(id int) (Problem, error)
+		GetProblemByTitle(title string) (Problem, error)
+		GetProblemByID(id int) (Problem, error)
+		GetProblemByTitleSlug(titleSlug string) (Problem, error)
 		GetDailyProblem() (Problem, error)
+
+		GetProblemByTitleContext(ctx context.Context, title string) (Problem, error)
+		GetProblemByIDContext(ctx context.Context, id int) (Problem, error)
+		GetProblemByTitleSlugContext(ctx context.Context, titleSlug string) (Problem, error)
+		GetDailyProblemContext(ctx context.Context) (Problem, error)
 	}
 
 	Problem struct {
-		ID        int // frontend id
-		Title     string
-		TitleSlug string
+		ID                 int    // internal question id, may differ from the frontend one
+		FrontendQuestionID string // id shown on the site, not always numeric
+		Title              string
+		TitleSlug          string
+		Content            string // statement HTML, see ContentMarkdown and ContentText
+
+		TranslatedTitle   string // set by sites serving translated statements, e.g. leetcode.cn
+		TranslatedContent string
 
-		MetaData     MetaData
-		CodeSnippets map[string]string // langSlug => code
-		Stats        Stats
-		EnvInfo      map[string]string // langSlug => envInfo
+		MetaData         MetaData
+		ExampleTestcases string            // raw input lines of example test cases
+		CodeSnippets     map[string]string // langSlug => code
+		Stats            Stats
+		EnvInfo          map[string]string // langSlug => envInfo
 
 		IsPaidOnly     bool
 		CanSeeQuestion bool
 		Difficulty     string
 		CategoryTitle  string
 		Hints          []string
+
+		Likes            int
+		Dislikes         int
+		ACRate           float64 // percent
+		TopicTags        []TopicTag
+		SimilarQuestions []SimilarQuestion
+		CompanyTags      []CompanyTag // premium only, sorted by times encountered
 	}
 
 	MetaData struct {
@@ -49,12 +76,44 @@ type (
 		TotalAccepted    int
 		TotalSubmissions int
 	}
+
+	TopicTag struct {
+		Name string
+		Slug string
+	}
+
+	SimilarQuestion struct {
+		Title      string
+		TitleSlug  string
+		Difficulty string
+	}
+
+	CompanyTag struct {
+		Name             string
+		Slug             string
+		TimesEncountered int // total over all time periods
+	}
 )
 
+// ContentMarkdown renders the statement as GitHub flavored Markdown.
+func (p *Problem) ContentMarkdown() string {
+	return content.Markdown(p.Content)
+}
+
+// ContentText renders the statement as plain text wrapped to width columns, non-positive width disables wrapping.
+func (p *Problem) ContentText(width int) string {
+	return content.Text(p.Content, width)
+}
+
 func externalProblemFromProblemData(data *problemData) (Problem, error) {
 	p := Problem{
-		Title:     data.Title,
-		TitleSlug: data.TitleSlug,
+		FrontendQuestionID: data.FrontendID,
+		Title:              data.Title,
+		TitleSlug:          data.TitleSlug,
+		Content:            data.Content,
+		TranslatedTitle:    data.TranslatedTitle,
+		TranslatedContent:  data.TranslatedContent,
+		ExampleTestcases:   data.ExampleTestcases,
 		Stats: Stats{
 			TotalAccepted:    data.Stats.TotalAcceptedRaw,
 			TotalSubmissions: data.Stats.TotalSubmissionsRaw,
@@ -64,6 +123,9 @@ func externalProblemFromProblemData(data *problemData) (Problem, error) {
 		Difficulty:     data.Difficulty,
 		CategoryTitle:  data.CategoryTitle,
 		Hints:          data.Hints,
+		Likes:          data.Likes,
+		Dislikes:       data.Dislikes,
+		ACRate:         data.ACRate,
 	}
 
 	id, err := strconv.Atoi(data.ID)
@@ -100,5 +162,46 @@ func externalProblemFromProblemData(data *problemData) (Problem, error) {
 	}
 	p.EnvInfo = envinfo
 
+	p.TopicTags = make([]TopicTag, 0, len(data.TopicTags))
+	for _, t := range data.TopicTags {
+		p.TopicTags = append(p.TopicTags, TopicTag(t))
+	}
+
+	p.SimilarQuestions = make([]SimilarQuestion, 0, len(data.SimilarQuestions))
+	for _, q := range data.SimilarQuestions {
+		p.SimilarQuestions = append(p.SimilarQuestions, SimilarQuestion(q))
+	}
+
+	p.CompanyTags = externalCompanyTags(data.CompanyTagStats)
+
 	return p, nil
 }
+
+// externalCompanyTags merges company tags of all time periods summing up times encountered.
+func externalCompanyTags(stats companyTagStats) []CompanyTag {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	tags := make([]CompanyTag, 0)
+	positions := make(map[string]int)
+	for _, periodTags := range stats {
+		for _, t := range periodTags {
+			if i, ok := positions[t.Slug]; ok {
+				tags[i].TimesEncountered += t.TimesEncountered
+				continue
+			}
+			positions[t.Slug] = len(tags)
+			tags = append(tags, CompanyTag(t))
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].TimesEncountered != tags[j].TimesEncountered {
+			return tags[i].TimesEncountered > tags[j].TimesEncountered
+		}
+		return tags[i].Slug < tags[j].Slug
+	})
+
+	return tags
+}
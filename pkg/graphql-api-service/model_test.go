@@ -15,31 +15,51 @@ func TestUnit_ExportProblemFromProblemData(t *testing.T) {
 	}{
 		"normal conversion": {
 			data: problemData{
-				ID:             "1",
-				Title:          "Test Problem",
-				TitleSlug:      "test-problem",
-				CodeSnippets:   []codeSnippet{{LangSlug: "golang", Code: "<golang code>"}},
-				Content:        "123",
-				IsPaidOnly:     false,
-				CanSeeQuestion: false,
-				Difficulty:     "easy",
-				CategoryTitle:  "Algorithms",
-				Hints:          []string{"hint #1", "hint #2"},
-				MetaDataRaw:    "", // these fields shouldn't be used
-				StatsRaw:       "",
-				EnvInfoRaw:     "",
+				ID:                "1",
+				FrontendID:        "1",
+				Title:             "Test Problem",
+				TitleSlug:         "test-problem",
+				TranslatedTitle:   "测试题",
+				TranslatedContent: "<p>内容</p>",
+				ExampleTestcases:  "[2,7,11,15]\n9",
+				CodeSnippets:      []codeSnippet{{LangSlug: "golang", Code: "<golang code>"}},
+				Content:           "123",
+				IsPaidOnly:        false,
+				CanSeeQuestion:    false,
+				Difficulty:        "easy",
+				CategoryTitle:     "Algorithms",
+				Hints:             []string{"hint #1", "hint #2"},
+				MetaDataRaw:       "", // these fields shouldn't be used
+				StatsRaw:          "",
+				EnvInfoRaw:        "",
 				MetaData: metaData{
 					Name:   "testProblem",
 					Params: []parameter{{Name: "input", Type: "integer[]"}},
 					Return: parameter{Type: "integer[]"},
 				},
-				Stats:   stats{TotalAcceptedRaw: 10, TotalSubmissionsRaw: 20},
-				EnvInfo: envInfo{"golang": {"Go", "<golang env info>"}},
+				Stats:     stats{TotalAcceptedRaw: 10, TotalSubmissionsRaw: 20},
+				EnvInfo:   envInfo{"golang": {"Go", "<golang env info>"}},
+				Likes:     100,
+				Dislikes:  5,
+				ACRate:    49.5,
+				TopicTags: []topicTag{{Name: "Array", Slug: "array"}},
+				SimilarQuestions: []similarQuestion{
+					{Title: "3Sum", TitleSlug: "3sum", Difficulty: "Medium"},
+				},
+				CompanyTagStats: companyTagStats{
+					"1": {{Name: "Amazon", Slug: "amazon", TimesEncountered: 3}, {Name: "Apple", Slug: "apple", TimesEncountered: 2}},
+					"2": {{Name: "Google", Slug: "google", TimesEncountered: 4}, {Name: "Amazon", Slug: "amazon", TimesEncountered: 2}},
+				},
 			},
 			expected: Problem{
-				ID:        1,
-				Title:     "Test Problem",
-				TitleSlug: "test-problem",
+				ID:                 1,
+				FrontendQuestionID: "1",
+				Title:              "Test Problem",
+				TitleSlug:          "test-problem",
+				Content:            "123",
+				TranslatedTitle:    "测试题",
+				TranslatedContent:  "<p>内容</p>",
+				ExampleTestcases:   "[2,7,11,15]\n9",
 				MetaData: MetaData{
 					FunctionName:    "testProblem",
 					InputParameters: []Parameter{{Name: "input", Type: "integer[]"}},
@@ -53,6 +73,18 @@ func TestUnit_ExportProblemFromProblemData(t *testing.T) {
 				Difficulty:     "easy",
 				CategoryTitle:  "Algorithms",
 				Hints:          []string{"hint #1", "hint #2"},
+				Likes:          100,
+				Dislikes:       5,
+				ACRate:         49.5,
+				TopicTags:      []TopicTag{{Name: "Array", Slug: "array"}},
+				SimilarQuestions: []SimilarQuestion{
+					{Title: "3Sum", TitleSlug: "3sum", Difficulty: "Medium"},
+				},
+				CompanyTags: []CompanyTag{
+					{Name: "Amazon", Slug: "amazon", TimesEncountered: 5},
+					{Name: "Google", Slug: "google", TimesEncountered: 4},
+					{Name: "Apple", Slug: "apple", TimesEncountered: 2},
+				},
 			},
 			err: nil,
 		},
@@ -81,3 +113,10 @@ func TestUnit_ExportProblemFromProblemData(t *testing.T) {
 		})
 	}
 }
+
+func TestUnit_ProblemContent(t *testing.T) {
+	p := Problem{Content: "<p>Return <code>10<sup>9</sup> + 7</code> <strong>modulo</strong> the answer.</p>"}
+	assert.Equal(t, "Return `10^9 + 7` **modulo** the answer.\n", p.ContentMarkdown())
+	assert.Equal(t, "Return 10^9 + 7\nmodulo the\nanswer.\n", p.ContentText(15))
+	assert.Equal(t, "", (&Problem{}).ContentMarkdown())
+}
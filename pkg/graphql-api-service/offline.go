@@ -0,0 +1,134 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	dailyDateLayout = "2006-01-02" // in the rollover location of the site
+)
+
+type (
+	// offlineStore is implemented by persistent caches, besides problems it keeps what offline clients
+	// can't request: the problem index for lookups by title and id and the history of daily problems.
+	offlineStore interface {
+		staleCache
+
+		dailyProblem(date string) (string, bool)
+		setDailyProblem(date, titleSlug string) error
+		storedProblemIndex() ([]problemTitleMap, bool)
+		setProblemIndex(refs []problemTitleMap) error
+	}
+)
+
+// dailyDate returns the date of the daily problem at t, the day starts at midnight in loc.
+func dailyDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(dailyDateLayout)
+}
+
+// requireOnline fails requests of offline clients before anything is sent.
+func (c *Client) requireOnline() error {
+	if c.offline {
+		return fmt.Errorf("%w: network requests are disabled", ErrorOffline)
+	}
+	return nil
+}
+
+func (c *Client) store() (offlineStore, bool) {
+	s, ok := c.problemCache.(offlineStore)
+	return s, ok
+}
+
+// offlineProblem serves the problem from the cache, expired entries included.
+func (c *Client) offlineProblem(titleSlug string) (Problem, error) {
+	if p, cacheHit := c.problemCache.Get(titleSlug); cacheHit {
+		return p, nil
+	}
+	if p, ok := c.staleProblem(titleSlug); ok {
+		return p, nil
+	}
+	return Problem{}, fmt.Errorf("%w: problem %s is not cached", ErrorOffline, titleSlug)
+}
+
+func (c *Client) offlineDailyProblemTitle(now time.Time) (string, error) {
+	date := dailyDate(now, c.site.dailyRollover)
+	if s, ok := c.store(); ok {
+		if titleSlug, ok := s.dailyProblem(date); ok {
+			return titleSlug, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no daily problem recorded for %s", ErrorOffline, date)
+}
+
+// recordDailyProblem keeps the daily problem for offline use.
+func (c *Client) recordDailyProblem(now time.Time, titleSlug string) {
+	s, ok := c.store()
+	if !ok {
+		return
+	}
+	date := dailyDate(now, c.site.dailyRollover)
+	if recorded, ok := s.dailyProblem(date); ok && recorded == titleSlug {
+		return
+	}
+	if err := s.setDailyProblem(date, titleSlug); err != nil {
+		c.logger.Printf("Error recording daily problem: %s", err)
+	}
+}
+
+// saveProblemIndex keeps the problem list for lookups by offline clients.
+func (c *Client) saveProblemIndex(refs []problemTitleMap) {
+	s, ok := c.store()
+	if !ok {
+		return
+	}
+	if err := s.setProblemIndex(refs); err != nil {
+		c.logger.Printf("Error saving problem index: %s", err)
+	}
+}
+
+// loadProblemIndex replaces the problem index with the stored one.
+func (c *Client) loadProblemIndex() error {
+	s, ok := c.store()
+	if !ok {
+		return fmt.Errorf("%w: cache keeps no problem index", ErrorOffline)
+	}
+	refs, ok := s.storedProblemIndex()
+	if !ok {
+		return fmt.Errorf("%w: no problem index stored", ErrorOffline)
+	}
+
+	idx := newProblemIndex(refs)
+	c.mu.Lock()
+	c.problemIndex = idx
+	c.mu.Unlock()
+
+	return nil
+}
+
+// lookupIndex returns the problem index, waiting for the first refresh of Run if it is still running.
+// Offline clients load it from the cache on first use.
+func (c *Client) lookupIndex(ctx context.Context) (*problemIndex, error) {
+	c.mu.RLock()
+	ready := c.indexReady
+	c.mu.RUnlock()
+	if ready != nil {
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for problem index: %w", ctx.Err())
+		}
+	}
+
+	if idx := c.index(); idx != nil {
+		return idx, nil
+	}
+	if !c.offline {
+		return nil, fmt.Errorf("%w: problem index is not initialized", ErrorSystem)
+	}
+	if err := c.loadProblemIndex(); err != nil {
+		return nil, err
+	}
+	return c.index(), nil
+}
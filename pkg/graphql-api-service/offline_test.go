@@ -0,0 +1,138 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_OfflineClient(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Now()
+	disk.now = func() time.Time { return now.Add(-2 * time.Hour) } // stale entries are served too
+	assert.NoError(t, disk.Put(&Problem{ID: 1, Title: "Two Sum", TitleSlug: "two-sum"}))
+	disk.now = time.Now
+	assert.NoError(t, disk.setProblemIndex([]problemTitleMap{
+		{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"},
+		{Title: "Add Two Numbers", TitleSlug: "add-two-numbers", ID: "2"},
+	}))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api, err := NewAPIClient(
+		WithHTTPClient(NewMockhttpClient(ctrl)), // no requests are expected
+		WithLogger(nil),
+		WithCache(NewLayeredCache(NewLRUCache(DefaultCacheConfig()), disk)),
+		WithOffline(),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx := context.Background()
+
+	p, err := api.GetProblemByTitleSlugContext(ctx, "two-sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "Two Sum", p.Title)
+
+	// the index is loaded on first use
+	p, err = api.GetProblemByTitleContext(ctx, "two sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+	p, err = api.GetProblemByIDContext(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+
+	_, err = api.GetProblemByIDContext(ctx, 2)
+	assert.ErrorIs(t, err, ErrorOffline)
+	assert.NotErrorIs(t, err, ErrorSystem)
+	_, err = api.GetProblemByIDContext(ctx, 3)
+	assert.ErrorIs(t, err, ErrorProblemNotFound)
+
+	_, err = api.GetDailyProblemContext(ctx)
+	assert.ErrorIs(t, err, ErrorOffline)
+	assert.NoError(t, disk.setDailyProblem(dailyDate(time.Now(), time.UTC), "two-sum"))
+	p, err = api.GetDailyProblemContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+
+	results := api.GetProblemsByTitleSlugs(ctx, []string{"two-sum", "add-two-numbers"})
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, ErrorOffline)
+
+	_, err = api.CurrentUserContext(ctx)
+	assert.ErrorIs(t, err, ErrorOffline)
+	assert.NotErrorIs(t, err, ErrorSystem)
+}
+
+func TestUnit_OfflineClientWithoutStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	api, err := NewAPIClient(WithHTTPClient(NewMockhttpClient(ctrl)), WithLogger(nil), WithOffline())
+	if !assert.NoError(t, err) {
+		return
+	}
+	ctx := context.Background()
+
+	api.refresh(ctx)
+	assert.Nil(t, api.index())
+
+	_, err = api.GetProblemByTitleContext(ctx, "Two Sum")
+	assert.ErrorIs(t, err, ErrorOffline)
+	_, err = api.GetProblemByTitleSlugContext(ctx, "two-sum")
+	assert.ErrorIs(t, err, ErrorOffline)
+	_, err = api.GetDailyProblemContext(ctx)
+	assert.ErrorIs(t, err, ErrorOffline)
+}
+
+func TestUnit_OnlineClientKeepsOfflineData(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.problemCache = disk
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	gomock.InOrder(
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"problemsetQuestionList":{"total":1}}}`,
+		), nil),
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"problemsetQuestionList":{"questions":[`+
+				`{"title":"Two Sum","titleSlug":"two-sum","frontendQuestionId":"1"}]}}}`,
+		), nil),
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"activeDailyCodingChallengeQuestion":{"question":{"titleSlug":"two-sum"}}}}`,
+		), nil),
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK,
+			`{"data":{"questionData":{"questionId":"1","title":"Two Sum","titleSlug":"two-sum"}}}`,
+		), nil),
+	)
+
+	assert.NoError(t, s.api.refreshTitleSlugMaps(context.Background()))
+	_, err = s.api.GetDailyProblemContext(context.Background())
+	assert.NoError(t, err)
+
+	refs, ok := disk.storedProblemIndex()
+	assert.True(t, ok)
+	assert.Equal(t, []problemTitleMap{{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"}}, refs)
+	titleSlug, ok := disk.dailyProblem(dailyDate(time.Now(), time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "two-sum", titleSlug)
+}
+
+func TestUnit_DailyDate(t *testing.T) {
+	// 16:30 UTC is past midnight in Beijing
+	now := time.Date(2024, 1, 1, 16, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "2024-01-01", dailyDate(now, sites[SiteGlobal].dailyRollover))
+	assert.Equal(t, "2024-01-02", dailyDate(now, sites[SiteChina].dailyRollover))
+}
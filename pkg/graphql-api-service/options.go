@@ -0,0 +1,133 @@
+package graphqlapiservice
+
+import (
+	"io"
+	"log"
+	"time"
+)
+
+type (
+	// Option configures the Client created by NewAPIClient.
+	Option func(c *Client)
+)
+
+// WithRetryPolicy replaces DefaultRetryPolicy, zero RetryPolicy disables retries.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithRateLimit limits all client requests to rate per second with given burst, non-positive rate disables limiting.
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(rate, burst)
+	}
+}
+
+// WithBaseURL points the client to another LeetCode host or a local stand-in server,
+// GraphQL endpoint and referers are derived from it. Defaults to the site URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient replaces the default http client, WithTimeout has no effect then.
+func WithHTTPClient(cli httpClient) Option {
+	return func(c *Client) {
+		c.cli = cli
+	}
+}
+
+// WithTimeout sets request timeout of the default http client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent sets User-Agent header of all requests.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithCache replaces the default LRUCache with DefaultCacheConfig limits, nil disables caching.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		if cache == nil {
+			cache = noCache{}
+		}
+		c.problemCache = cache
+	}
+}
+
+// WithLogger replaces log.Default for service messages.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = log.New(io.Discard, "", 0)
+		}
+		c.logger = logger
+	}
+}
+
+// WithSite selects LeetCode backend, SiteGlobal by default. NewAPIClient fails for unknown sites.
+func WithSite(site Site) Option {
+	return func(c *Client) {
+		cfg, ok := sites[site]
+		if !ok {
+			// rejected by NewAPIClient
+			cfg = &siteConfig{site: site}
+		}
+		c.site = cfg
+	}
+}
+
+// WithSession signs requests with cookies of a browser session, see SessionFromEnv and SessionFromCookieFile.
+func WithSession(s Session) Option {
+	return func(c *Client) {
+		c.session = &s
+	}
+}
+
+// WithJudgePolling sets how often judge results are checked and how long to wait for them,
+// non-positive values keep the defaults.
+func WithJudgePolling(interval, timeout time.Duration) Option {
+	return func(c *Client) {
+		if interval > 0 {
+			c.pollInterval = interval
+		}
+		if timeout > 0 {
+			c.pollTimeout = timeout
+		}
+	}
+}
+
+// WithOffline makes the client answer from the cache only, without any network requests.
+// Problems, the problem index and daily problems are served from a persistent cache such as DiskCache
+// filled by an online client or an imported snapshot, anything else fails with ErrorOffline.
+func WithOffline() Option {
+	return func(c *Client) {
+		c.offline = true
+	}
+}
+
+// WithPrefetch makes Run keep the daily problem and the listed problems in the cache.
+func WithPrefetch(cfg PrefetchConfig) Option {
+	return func(c *Client) {
+		cfg = cfg.withDefaults()
+		c.prefetch = &cfg
+	}
+}
+
+// WithBatchSize sets how many problems GetProblemsByTitleSlugs requests at once, non-positive sizes are ignored.
+func WithBatchSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.batchSize = size
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_NewAPIClientOptions(t *testing.T) {
+	c, err := NewAPIClient()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultBaseURL, c.baseURL)
+	assert.Equal(t, defaultTimeout, c.cli.(*http.Client).Timeout)
+	assert.NotNil(t, c.limiter)
+	assert.Equal(t, defaultBatchSize, c.batchSize)
+
+	c, err = NewAPIClient(
+		WithBaseURL("http://localhost:8080/"),
+		WithTimeout(time.Second),
+		WithUserAgent("leetcode-tools"),
+		WithCache(nil),
+		WithRateLimit(0, 0),
+		WithBatchSize(5),
+		WithBatchSize(0),
+		WithJudgePolling(time.Millisecond, 0),
+		WithJudgePolling(0, -time.Second),
+		WithPrefetch(PrefetchConfig{Daily: true, Interval: time.Hour}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL)
+	assert.Equal(t, time.Second, c.cli.(*http.Client).Timeout)
+	assert.Equal(t, "leetcode-tools", c.userAgent)
+	assert.Nil(t, c.limiter)
+	assert.Equal(t, 5, c.batchSize)
+	assert.Equal(t, time.Millisecond, c.pollInterval)
+	assert.Equal(t, defaultPollTimeout, c.pollTimeout)
+	assert.Equal(t, &PrefetchConfig{Daily: true, DailyDelay: defaultPrefetchDailyDelay, Interval: time.Hour}, c.prefetch)
+
+	c.problemCache.Add(&Problem{TitleSlug: "two-sum"})
+	_, ok := c.problemCache.Get("two-sum")
+	assert.False(t, ok)
+
+	_, err = NewAPIClient(WithBaseURL("localhost"))
+	assert.Error(t, err)
+}
+
+func TestUnit_BaseURLStandIn(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "leetcode-tools", r.UserAgent())
+		http.SetCookie(w, &http.Cookie{Name: csrfTokenCookie, Value: "token"})
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "leetcode-tools", r.UserAgent())
+		assert.Equal(t, "token", r.Header.Get(csrfTokenHeader))
+		assert.Equal(t, "http://"+r.Host+problemListReferer, r.Referer())
+		_, err := w.Write([]byte(`{"data":{"activeDailyCodingChallengeQuestion":{"question":{"titleSlug":"two-sum"}}}}`))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := NewAPIClient(WithBaseURL(server.URL), WithUserAgent("leetcode-tools"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.refreshCSRFToken(context.Background()))
+	titleSlug, err := c.getDailyProblemTitle(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", titleSlug)
+}
@@ -0,0 +1,105 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	defaultPrefetchInterval   = 30 * time.Minute
+	defaultPrefetchDailyDelay = 5 * time.Minute
+)
+
+type (
+	// PrefetchConfig lists problems Run keeps in the cache, so that callers don't wait for the API.
+	PrefetchConfig struct {
+		Daily      bool          // fetch the daily problem right after it changes at midnight of the site, UTC or UTC+8 for leetcode.cn
+		DailyDelay time.Duration // after midnight, the site takes a moment to switch, defaults to 5 minutes
+
+		TitleSlugs []string
+		Filter     *ProblemFilter // problems matching the filter, listed anew on every prefetch
+		Interval   time.Duration  // between prefetches of listed problems, defaults to 30 minutes
+	}
+)
+
+func (cfg PrefetchConfig) withDefaults() PrefetchConfig {
+	if cfg.DailyDelay <= 0 {
+		cfg.DailyDelay = defaultPrefetchDailyDelay
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultPrefetchInterval
+	}
+	return cfg
+}
+
+// runPrefetcher warms the cache right away and then keeps it warm until ctx is done.
+func (c *Client) runPrefetcher(ctx context.Context, cfg PrefetchConfig) {
+	c.prefetchProblems(ctx, cfg)
+
+	var daily *time.Timer
+	var dailyC <-chan time.Time // nil unless the daily problem is prefetched
+	if cfg.Daily {
+		c.prefetchDailyProblem(ctx)
+		daily = time.NewTimer(untilDailyRollover(time.Now(), c.site.dailyRollover, cfg.DailyDelay))
+		defer daily.Stop()
+		dailyC = daily.C
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.prefetchProblems(ctx, cfg)
+		case <-dailyC:
+			c.prefetchDailyProblem(ctx)
+			daily.Reset(untilDailyRollover(time.Now(), c.site.dailyRollover, cfg.DailyDelay))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// prefetchProblems fetches listed problems missing from the cache or expired.
+func (c *Client) prefetchProblems(ctx context.Context, cfg PrefetchConfig) {
+	titleSlugs := append([]string(nil), cfg.TitleSlugs...)
+	if cfg.Filter != nil {
+		problems, err := c.ListProblems(ctx, *cfg.Filter)
+		if err != nil {
+			c.logger.Printf("Error listing problems to prefetch: %s", err)
+		}
+		for _, p := range problems {
+			titleSlugs = append(titleSlugs, p.TitleSlug)
+		}
+	}
+	if len(titleSlugs) == 0 {
+		return
+	}
+
+	failed := 0
+	for _, r := range c.GetProblemsByTitleSlugs(ctx, titleSlugs) {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		c.logger.Printf("Error prefetching problems: %d of %d failed", failed, len(titleSlugs))
+	}
+}
+
+func (c *Client) prefetchDailyProblem(ctx context.Context) {
+	if _, err := c.GetDailyProblemContext(ctx); err != nil {
+		c.logger.Printf("Error prefetching daily problem: %s", err)
+	}
+}
+
+// untilDailyRollover returns the time left until delay past the next midnight in loc.
+func untilDailyRollover(now time.Time, loc *time.Location, delay time.Duration) time.Duration {
+	year, month, day := now.In(loc).Date()
+	next := time.Date(year, month, day, 0, 0, 0, 0, loc).Add(delay)
+	if !now.Before(next) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next.Sub(now)
+}
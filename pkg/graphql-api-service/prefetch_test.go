@@ -0,0 +1,91 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_UntilDailyRollover(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		now      time.Time
+		loc      *time.Location
+		delay    time.Duration
+		expected time.Duration
+	}{
+		"before rollover": {
+			now:      day.Add(23 * time.Hour),
+			delay:    5 * time.Minute,
+			expected: time.Hour + 5*time.Minute,
+		},
+		"after midnight within delay": {
+			now:      day.Add(2 * time.Minute),
+			delay:    5 * time.Minute,
+			expected: 3 * time.Minute,
+		},
+		"right at rollover": {
+			now:      day.Add(5 * time.Minute),
+			delay:    5 * time.Minute,
+			expected: 24 * time.Hour,
+		},
+		"other time zone": {
+			now:      day.Add(23 * time.Hour).In(time.FixedZone("UTC+8", 8*60*60)),
+			expected: time.Hour,
+		},
+		"rollover location": {
+			now:      day.Add(15 * time.Hour),
+			loc:      chinaDailyRollover,
+			delay:    5 * time.Minute,
+			expected: time.Hour + 5*time.Minute,
+		},
+		"rollover location past midnight UTC": {
+			now:      day.Add(time.Hour),
+			loc:      chinaDailyRollover,
+			expected: 15 * time.Hour,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			loc := test.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			assert.Equal(t, test.expected, untilDailyRollover(test.now, loc, test.delay))
+		})
+	}
+}
+
+func TestUnit_PrefetchProblems(t *testing.T) {
+	var requests int32
+	handler := snapshotHandler(t, 5)
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		graphqlAPIEndpoint: func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			handler(w, r)
+		},
+	})
+	cfg := PrefetchConfig{
+		TitleSlugs: []string{"problem-5"},
+		Filter:     &ProblemFilter{Limit: 2},
+	}
+
+	c.prefetchProblems(context.Background(), cfg)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "problem list and a single batch")
+	for _, titleSlug := range []string{"problem-1", "problem-2", "problem-5"} {
+		_, ok := c.problemCache.Get(titleSlug)
+		assert.True(t, ok, titleSlug)
+	}
+	_, ok := c.problemCache.Get("problem-3")
+	assert.False(t, ok)
+
+	// cached problems are not requested again
+	c.prefetchProblems(context.Background(), cfg)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
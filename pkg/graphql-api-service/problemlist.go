@@ -0,0 +1,241 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	defaultProblemPageSize = 100
+
+	variableSkip = "skip"
+
+	DifficultyEasy   Difficulty = "EASY"
+	DifficultyMedium Difficulty = "MEDIUM"
+	DifficultyHard   Difficulty = "HARD"
+
+	ProblemStatusSolved    ProblemStatus = "AC"
+	ProblemStatusAttempted ProblemStatus = "TRIED"
+	ProblemStatusTodo      ProblemStatus = "NOT_STARTED"
+
+	OrderByFrontendID OrderBy = "FRONTEND_ID"
+	OrderByACRate     OrderBy = "AC_RATE"
+	OrderByDifficulty OrderBy = "DIFFICULTY"
+
+	sortOrderAscending  = "ASCENDING"
+	sortOrderDescending = "DESCENDING"
+)
+
+type (
+	Difficulty    string
+	ProblemStatus string // status of the problem for the signed-in user
+	OrderBy       string
+
+	// ProblemFilter mirrors QuestionListFilterInput of the problem list page, zero value matches all problems.
+	ProblemFilter struct {
+		CategorySlug   string // e.g. "algorithms", "database", empty for all
+		Difficulty     Difficulty
+		Tags           []string // topic tag slugs, problems must have all of them
+		Status         ProblemStatus
+		PremiumOnly    *bool // nil for both free and premium problems
+		SearchKeywords string
+		ListID         string // id of a problem list, e.g. a study plan or favorites
+		OrderBy        OrderBy
+		Descending     bool
+
+		Skip     int // problems skipped from the start of the list
+		Limit    int // max number of problems returned, 0 for no limit
+		PageSize int // problems requested at once, defaults to 100
+	}
+
+	// ProblemSummary is a row of the problem list.
+	ProblemSummary struct {
+		FrontendID      string
+		Title           string
+		TitleSlug       string
+		TranslatedTitle string // leetcode.cn only
+		Difficulty      string
+		ACRate          float64 // percent
+		PaidOnly        bool
+		Status          ProblemStatus // empty for anonymous clients
+		TopicTags       []TopicTag
+	}
+
+	// ProblemIterator streams the problem list page by page, every page is a separate rate limited request.
+	ProblemIterator struct {
+		c      *Client
+		filter ProblemFilter
+
+		page     []ProblemSummary
+		skip     int
+		total    int
+		hasNext  bool
+		returned int
+
+		current ProblemSummary
+		err     error
+	}
+
+	problemListFilters struct {
+		Difficulty     Difficulty    `json:"difficulty,omitempty"`
+		Tags           []string      `json:"tags,omitempty"`
+		Status         ProblemStatus `json:"status,omitempty"`
+		PremiumOnly    *bool         `json:"premiumOnly,omitempty"`
+		SearchKeywords string        `json:"searchKeywords,omitempty"`
+		ListID         string        `json:"listId,omitempty"`
+		OrderBy        OrderBy       `json:"orderBy,omitempty"`
+		SortOrder      string        `json:"sortOrder,omitempty"`
+	}
+
+	topicTag struct {
+		Name string
+		Slug string
+	}
+)
+
+func (f *ProblemFilter) variables(skip, limit int) map[string]interface{} {
+	filters := problemListFilters{
+		Difficulty:     f.Difficulty,
+		Tags:           f.Tags,
+		Status:         f.Status,
+		PremiumOnly:    f.PremiumOnly,
+		SearchKeywords: f.SearchKeywords,
+		ListID:         f.ListID,
+		OrderBy:        f.OrderBy,
+	}
+	if f.OrderBy != "" {
+		filters.SortOrder = sortOrderAscending
+		if f.Descending {
+			filters.SortOrder = sortOrderDescending
+		}
+	}
+
+	return map[string]interface{}{
+		variableCategorySlug: f.CategorySlug,
+		variableSkip:         skip,
+		variableLimit:        limit,
+		variableFilters:      filters,
+	}
+}
+
+// ListProblems returns all problems matching the filter.
+func (c *Client) ListProblems(ctx context.Context, filter ProblemFilter) ([]ProblemSummary, error) {
+	var problems []ProblemSummary
+	it := c.IterateProblems(filter)
+	for it.Next(ctx) {
+		problems = append(problems, it.Problem())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// IterateProblems returns an iterator over problems matching the filter.
+func (c *Client) IterateProblems(filter ProblemFilter) *ProblemIterator {
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultProblemPageSize
+	}
+	return &ProblemIterator{
+		c:       c,
+		filter:  filter,
+		skip:    filter.Skip,
+		hasNext: true,
+	}
+}
+
+// Next advances the iterator, fetching the next page when needed. It returns false once
+// problems are exhausted, the limit is reached or an error occurs, see Err.
+func (it *ProblemIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.filter.Limit > 0 && it.returned >= it.filter.Limit) {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if !it.hasNext {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	it.returned++
+	return true
+}
+
+func (it *ProblemIterator) Problem() ProblemSummary {
+	return it.current
+}
+
+func (it *ProblemIterator) Err() error {
+	return it.err
+}
+
+// Total is the number of problems matching the filter, known after the first page is fetched.
+func (it *ProblemIterator) Total() int {
+	return it.total
+}
+
+func (it *ProblemIterator) fetch(ctx context.Context) error {
+	limit := it.filter.PageSize
+	if it.filter.Limit > 0 && it.filter.Limit-it.returned < limit {
+		limit = it.filter.Limit - it.returned
+	}
+
+	req, err := it.c.newRequest(ctx, it.c.site.problemFilterQuery, it.filter.variables(it.skip, limit))
+	if err != nil {
+		return wrapAPIError(err, "init request")
+	}
+	it.c.addRefererHeader(req, it.c.baseURL+it.c.site.problemListReferer)
+
+	data, err := it.c.doRequest(req)
+	if err != nil {
+		return wrapAPIError(err, "query problem list")
+	}
+
+	parsedResponse := &problemFilterQueryData{}
+	if err = json.Unmarshal(data, parsedResponse); err != nil {
+		return fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+	}
+	list := parsedResponse.ProblemsetQuestionList
+	if list == nil {
+		return fmt.Errorf("%w: empty problem list", ErrorSystem)
+	}
+	translatedTitles, err := decodeTranslatedTitles(data, len(list.Questions))
+	if err != nil {
+		return fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+	}
+
+	it.total = list.Total
+	it.skip += len(list.Questions)
+	it.hasNext = len(list.Questions) == limit && it.skip < list.Total
+
+	it.page = make([]ProblemSummary, 0, len(list.Questions))
+	for i, q := range list.Questions {
+		p := ProblemSummary{
+			FrontendID:      q.FrontendQuestionID,
+			Title:           q.Title,
+			TitleSlug:       q.TitleSlug,
+			TranslatedTitle: translatedTitles[i],
+			Difficulty:      q.Difficulty,
+			ACRate:          q.AcRate,
+			PaidOnly:        q.PaidOnly,
+			Status:          ProblemStatus(q.Status),
+			TopicTags:       make([]TopicTag, 0, len(q.TopicTags)),
+		}
+		for _, t := range q.TopicTags {
+			p.TopicTags = append(p.TopicTags, TopicTag(t))
+		}
+		it.page = append(it.page, p)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ProblemFilterVariables(t *testing.T) {
+	premium := true
+	f := ProblemFilter{
+		CategorySlug:   "algorithms",
+		Difficulty:     DifficultyMedium,
+		Tags:           []string{"array", "hash-table"},
+		Status:         ProblemStatusTodo,
+		PremiumOnly:    &premium,
+		SearchKeywords: "sum",
+		ListID:         "wpwgkgt",
+		OrderBy:        OrderByACRate,
+		Descending:     true,
+	}
+
+	data, err := json.Marshal(f.variables(50, 25))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"categorySlug": "algorithms",
+		"skip": 50,
+		"limit": 25,
+		"filters": {
+			"difficulty": "MEDIUM",
+			"tags": ["array", "hash-table"],
+			"status": "NOT_STARTED",
+			"premiumOnly": true,
+			"searchKeywords": "sum",
+			"listId": "wpwgkgt",
+			"orderBy": "AC_RATE",
+			"sortOrder": "DESCENDING"
+		}
+	}`, string(data))
+
+	empty := ProblemFilter{}
+	data, err = json.Marshal(empty.variables(0, 100))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"categorySlug": "", "skip": 0, "limit": 100, "filters": {}}`, string(data))
+}
+
+// problemListHandler serves a list of total problems named "Problem N", honoring skip and limit.
+func problemListHandler(t *testing.T, total int, requests *[]queryVariables) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := graphQLRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+		*requests = append(*requests, q.Variables)
+
+		skip := int(q.Variables[variableSkip].(float64))
+		limit := int(q.Variables[variableLimit].(float64))
+		questions := make([]string, 0, limit)
+		for i := skip + 1; i <= total && i <= skip+limit; i++ {
+			questions = append(questions, fmt.Sprintf(
+				`{"acRate":50.5,"difficulty":"Easy","frontendQuestionId":"%d","paidOnly":false,"status":null,`+
+					`"title":"Problem %d","titleSlug":"problem-%d","topicTags":[{"name":"Array","slug":"array"}]}`,
+				i, i, i,
+			))
+		}
+		writeJSON(t, w, fmt.Sprintf(
+			`{"data":{"problemsetQuestionList":{"total":%d,"questions":[%s]}}}`,
+			total, strings.Join(questions, ","),
+		))
+	}
+}
+
+func TestUnit_ListProblems(t *testing.T) {
+	var requests []queryVariables
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": problemListHandler(t, 5, &requests),
+	})
+
+	problems, err := c.ListProblems(context.Background(), ProblemFilter{PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, problems, 5)
+	assert.Len(t, requests, 3)
+	assert.Equal(t, ProblemSummary{
+		FrontendID: "1",
+		Title:      "Problem 1",
+		TitleSlug:  "problem-1",
+		Difficulty: "Easy",
+		ACRate:     50.5,
+		TopicTags:  []TopicTag{{Name: "Array", Slug: "array"}},
+	}, problems[0])
+	assert.Equal(t, "problem-5", problems[4].TitleSlug)
+
+	requests = nil
+	problems, err = c.ListProblems(context.Background(), ProblemFilter{Skip: 1, Limit: 3, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, problems, 3)
+	assert.Equal(t, "problem-2", problems[0].TitleSlug)
+	assert.Equal(t, "problem-4", problems[2].TitleSlug)
+	assert.Equal(t, float64(1), requests[1][variableLimit]) // last page is cut to the limit
+
+	it := c.IterateProblems(ProblemFilter{})
+	assert.Equal(t, 0, it.Total())
+	assert.True(t, it.Next(context.Background()))
+	assert.Equal(t, 5, it.Total())
+}
+
+func TestUnit_ListProblemsError(t *testing.T) {
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	})
+
+	_, err := c.ListProblems(context.Background(), ProblemFilter{})
+	assert.ErrorIs(t, err, ErrorRateLimited)
+}
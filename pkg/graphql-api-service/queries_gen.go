@@ -0,0 +1,283 @@
+// Code generated by querygen. DO NOT EDIT.
+
+package graphqlapiservice
+
+const (
+	// dailyProblemQuery is generated from graphql/daily_problem.graphql.
+	dailyProblemQuery = `query questionOfToday {
+	activeDailyCodingChallengeQuestion {
+		question {
+			titleSlug
+		}
+	}
+}
+`
+	// judgeQuestionQuery is generated from graphql/judge_question.graphql.
+	judgeQuestionQuery = `query judgeQuestion($titleSlug: String!) {
+	questionData: question(titleSlug: $titleSlug) {
+		questionId
+		exampleTestcases
+	}
+}
+`
+	// problemByTitleSlugQuery is generated from graphql/problem_by_title_slug.graphql.
+	problemByTitleSlugQuery = `query questionData($titleSlug: String!) {
+	questionData: question(titleSlug: $titleSlug) {
+		questionId
+		questionFrontendId
+		title
+		titleSlug
+		likes
+		dislikes
+		acRate
+		topicTags {
+			name
+			slug
+		}
+		similarQuestions
+		companyTagStats
+		exampleTestcases
+		codeSnippets {
+			langSlug
+			code
+		}
+		content
+		isPaidOnly
+		canSeeQuestion
+		difficulty
+		categoryTitle
+		hints
+		metaData
+		stats
+		envInfo
+	}
+}
+`
+	// problemFilterQuery is generated from graphql/problem_filter.graphql.
+	problemFilterQuery = `query problemsetQuestionList($categorySlug: String, $limit: Int, $skip: Int, $filters: QuestionListFilterInput) {
+	problemsetQuestionList: questionList(
+		categorySlug: $categorySlug
+		limit: $limit
+		skip: $skip
+		filters: $filters
+	) {
+		total: totalNum
+		questions: data {
+			acRate
+			difficulty
+			frontendQuestionId: questionFrontendId
+			paidOnly: isPaidOnly
+			status
+			title
+			titleSlug
+			topicTags {
+				name
+				slug
+			}
+		}
+	}
+}
+`
+	// problemListQuery is generated from graphql/problem_list.graphql.
+	problemListQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput, $limit: Int) {
+	problemsetQuestionList: questionList(
+		categorySlug: $categorySlug
+		filters: $filters
+		limit: $limit
+	) {
+		questions: data {
+			title
+			titleSlug
+			frontendQuestionId: questionFrontendId
+		}
+	}
+}
+`
+	// submissionDetailsQuery is generated from graphql/submission_details.graphql.
+	submissionDetailsQuery = `query submissionDetails($submissionId: Int!) {
+	submissionDetails(submissionId: $submissionId) {
+		runtimeDisplay
+		memoryDisplay
+		code
+		timestamp
+		statusCode
+		lang {
+			name
+			verboseName
+		}
+		question {
+			title
+			titleSlug
+		}
+	}
+}
+`
+	// submissionListQuery is generated from graphql/submission_list.graphql.
+	submissionListQuery = `query submissionList($offset: Int!, $limit: Int!, $lastKey: String, $questionSlug: String) {
+	submissionList(offset: $offset, limit: $limit, lastKey: $lastKey, questionSlug: $questionSlug) {
+		lastKey
+		hasNext
+		submissions {
+			id
+			title
+			titleSlug
+			status
+			statusDisplay
+			lang
+			langName
+			runtime
+			memory
+			timestamp
+		}
+	}
+}
+`
+	// totalProblemsQuery is generated from graphql/total_problems.graphql.
+	totalProblemsQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput) {
+	problemsetQuestionList: questionList(
+		categorySlug: $categorySlug
+		filters: $filters
+	) {
+		total: totalNum
+	}
+}
+`
+	// userStatusQuery is generated from graphql/user_status.graphql.
+	userStatusQuery = `query globalData {
+	userStatus {
+		username
+		isSignedIn
+		isPremium
+	}
+}
+`
+)
+
+type (
+	dailyProblemQueryData struct {
+		ActiveDailyCodingChallengeQuestion *struct {
+			Question struct {
+				TitleSlug string `json:"titleSlug"`
+			} `json:"question"`
+		} `json:"activeDailyCodingChallengeQuestion"`
+	}
+
+	judgeQuestionQueryData struct {
+		QuestionData *struct {
+			QuestionID       string `json:"questionId"`
+			ExampleTestcases string `json:"exampleTestcases"`
+		} `json:"questionData"`
+	}
+
+	problemByTitleSlugQueryData struct {
+		QuestionData *struct {
+			QuestionID         string  `json:"questionId"`
+			QuestionFrontendID string  `json:"questionFrontendId"`
+			Title              string  `json:"title"`
+			TitleSlug          string  `json:"titleSlug"`
+			Likes              int     `json:"likes"`
+			Dislikes           int     `json:"dislikes"`
+			AcRate             float64 `json:"acRate"`
+			TopicTags          []struct {
+				Name string `json:"name"`
+				Slug string `json:"slug"`
+			} `json:"topicTags"`
+			SimilarQuestions string `json:"similarQuestions"`
+			CompanyTagStats  string `json:"companyTagStats"`
+			ExampleTestcases string `json:"exampleTestcases"`
+			CodeSnippets     []struct {
+				LangSlug string `json:"langSlug"`
+				Code     string `json:"code"`
+			} `json:"codeSnippets"`
+			Content        string   `json:"content"`
+			IsPaidOnly     bool     `json:"isPaidOnly"`
+			CanSeeQuestion bool     `json:"canSeeQuestion"`
+			Difficulty     string   `json:"difficulty"`
+			CategoryTitle  string   `json:"categoryTitle"`
+			Hints          []string `json:"hints"`
+			MetaData       string   `json:"metaData"`
+			Stats          string   `json:"stats"`
+			EnvInfo        string   `json:"envInfo"`
+		} `json:"questionData"`
+	}
+
+	problemFilterQueryData struct {
+		ProblemsetQuestionList *struct {
+			Total     int `json:"total"`
+			Questions []struct {
+				AcRate             float64 `json:"acRate"`
+				Difficulty         string  `json:"difficulty"`
+				FrontendQuestionID string  `json:"frontendQuestionId"`
+				PaidOnly           bool    `json:"paidOnly"`
+				Status             string  `json:"status"`
+				Title              string  `json:"title"`
+				TitleSlug          string  `json:"titleSlug"`
+				TopicTags          []struct {
+					Name string `json:"name"`
+					Slug string `json:"slug"`
+				} `json:"topicTags"`
+			} `json:"questions"`
+		} `json:"problemsetQuestionList"`
+	}
+
+	problemListQueryData struct {
+		ProblemsetQuestionList *struct {
+			Questions []struct {
+				Title              string `json:"title"`
+				TitleSlug          string `json:"titleSlug"`
+				FrontendQuestionID string `json:"frontendQuestionId"`
+			} `json:"questions"`
+		} `json:"problemsetQuestionList"`
+	}
+
+	submissionDetailsQueryData struct {
+		SubmissionDetails *struct {
+			RuntimeDisplay string `json:"runtimeDisplay"`
+			MemoryDisplay  string `json:"memoryDisplay"`
+			Code           string `json:"code"`
+			Timestamp      int    `json:"timestamp"`
+			StatusCode     int    `json:"statusCode"`
+			Lang           *struct {
+				Name        string `json:"name"`
+				VerboseName string `json:"verboseName"`
+			} `json:"lang"`
+			Question *struct {
+				Title     string `json:"title"`
+				TitleSlug string `json:"titleSlug"`
+			} `json:"question"`
+		} `json:"submissionDetails"`
+	}
+
+	submissionListQueryData struct {
+		SubmissionList *struct {
+			LastKey     string `json:"lastKey"`
+			HasNext     bool   `json:"hasNext"`
+			Submissions []struct {
+				ID            string `json:"id"`
+				Title         string `json:"title"`
+				TitleSlug     string `json:"titleSlug"`
+				Status        int    `json:"status"`
+				StatusDisplay string `json:"statusDisplay"`
+				Lang          string `json:"lang"`
+				LangName      string `json:"langName"`
+				Runtime       string `json:"runtime"`
+				Memory        string `json:"memory"`
+				Timestamp     string `json:"timestamp"`
+			} `json:"submissions"`
+		} `json:"submissionList"`
+	}
+
+	totalProblemsQueryData struct {
+		ProblemsetQuestionList *struct {
+			Total int `json:"total"`
+		} `json:"problemsetQuestionList"`
+	}
+
+	userStatusQueryData struct {
+		UserStatus *struct {
+			Username   string `json:"username"`
+			IsSignedIn bool   `json:"isSignedIn"`
+			IsPremium  bool   `json:"isPremium"`
+		} `json:"userStatus"`
+	}
+)
@@ -0,0 +1,32 @@
+package graphqlapiservice
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/gqlgen"
+)
+
+// Queries of leetcode.com are validated against graphql/schema.json during generation,
+// leetcode.cn ones in site.go are hand-written as there is no snapshot of that schema.
+func TestUnit_GeneratedQueriesUpToDate(t *testing.T) {
+	schema, err := gqlgen.LoadSchemaFile("graphql/schema.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	queries, err := gqlgen.LoadQueries("graphql")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	src, err := gqlgen.Generate(schema, "graphqlapiservice", queries)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	current, err := os.ReadFile("queries_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(src), string(current), "queries_gen.go is stale, run go generate")
+}
@@ -0,0 +1,137 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultRateLimit = 5 // requests per second
+	defaultRateBurst = 10
+)
+
+type (
+	// rateLimiter is a token bucket shared by all requests of a Client.
+	// Nil limiter does not limit anything.
+	rateLimiter struct {
+		mu     sync.Mutex
+		rate   float64 // tokens per second
+		burst  float64
+		tokens float64
+		last   time.Time
+		now    func() time.Time
+
+		waitNanos atomic.Int64
+		waits     atomic.Int64
+	}
+)
+
+// newRateLimiter returns nil for non-positive rate.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before using it.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve that won't be used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	start := l.now()
+	delay := l.reserve()
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(start) < delay {
+		l.cancel()
+		return context.DeadlineExceeded
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		// the reserved delay rather than the time measured, which includes scheduling
+		l.waits.Add(1)
+		l.waitNanos.Add(int64(delay))
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		l.waitNanos.Add(int64(l.now().Sub(start)))
+		return ctx.Err()
+	}
+}
+
+func (l *rateLimiter) waitTime() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return time.Duration(l.waitNanos.Load())
+}
+
+func (l *rateLimiter) waitCount() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.waits.Load()
+}
+
+// send passes the request to the http client once the rate limiter allows it.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if err := c.requireOnline(); err != nil {
+		return nil, err
+	}
+	if err := c.limiter.wait(req.Context()); err != nil {
+		return nil, fmt.Errorf("rate limiter: %w", err)
+	}
+	if c.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	return c.cli.Do(req)
+}
@@ -0,0 +1,92 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeClockRateLimiter returns a limiter whose clock only moves when the test moves it.
+func newFakeClockRateLimiter(rate float64, burst int) (*rateLimiter, *time.Time) {
+	l := newRateLimiter(rate, burst)
+	now := l.last
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestUnit_RateLimiterReserve(t *testing.T) {
+	l, now := newFakeClockRateLimiter(10, 2)
+
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, 100*time.Millisecond, l.reserve())
+	assert.Equal(t, 200*time.Millisecond, l.reserve())
+
+	// tokens are refilled over time
+	*now = now.Add(250 * time.Millisecond)
+	assert.Equal(t, 50*time.Millisecond, l.reserve())
+
+	// but never above burst
+	*now = now.Add(time.Hour)
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, 100*time.Millisecond, l.reserve())
+
+	assert.Nil(t, newRateLimiter(0, 10))
+}
+
+func TestUnit_RateLimiterWait(t *testing.T) {
+	l, now := newFakeClockRateLimiter(100, 1)
+
+	start := time.Now()
+	assert.NoError(t, l.wait(context.Background()))
+	assert.NoError(t, l.wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.Equal(t, int64(1), l.waitCount())
+	assert.Equal(t, 10*time.Millisecond, l.waitTime())
+
+	// deadline is too close to wait for a token, which is given back
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	*now = deadline.Add(-5 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.ErrorIs(t, l.wait(ctx), context.DeadlineExceeded)
+	*now = now.Add(10 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), l.reserve())
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.wait(canceled), context.Canceled)
+
+	var unlimited *rateLimiter
+	assert.NoError(t, unlimited.wait(context.Background()))
+	assert.Equal(t, time.Duration(0), unlimited.waitTime())
+}
+
+func TestUnit_ClientRateLimit(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	// requests are sent at the same instant, the second waits for 10ms and the third for 20ms
+	s.api.limiter, _ = newFakeClockRateLimiter(100, 1)
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "token"}
+
+	s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+		return newJSONResponse(http.StatusOK, `{"data":1}`), nil
+	}).Times(3)
+
+	for i := 0; i < 3; i++ {
+		req, err := s.api.newRequest(context.Background(), dailyProblemQuery, nil)
+		assert.NoError(t, err)
+		_, err = s.api.doRequest(req)
+		assert.NoError(t, err)
+	}
+
+	m := s.api.Metrics()
+	assert.Equal(t, int64(2), m.RateLimitedCalls)
+	assert.Equal(t, 30*time.Millisecond, m.RateLimitWait)
+}
@@ -0,0 +1,228 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryAttempts  = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+	defaultRetryJitter    = 0.2
+)
+
+type (
+	// RetryPolicy controls how failed API requests are retried.
+	// Zero value disables retries. Requests with side effects, e.g. submissions, are retried only when
+	// the failed attempt was not sent to the server.
+	RetryPolicy struct {
+		MaxAttempts int           // total number of attempts including the first one
+		BaseDelay   time.Duration // delay before the second attempt, doubled for each following one
+		MaxDelay    time.Duration // upper bound for a single delay, longer Retry-After values stop retrying
+		Jitter      float64       // fraction of the delay randomized in both directions, 0..1
+
+		RetryableStatusCodes []int
+	}
+)
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: defaultRetryAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		Jitter:      defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// backoff returns the delay before the next attempt, attempt is 1-based number of the failed one.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		//nolint:gosec // jitter does not need a secure source
+		delta := (rand.Float64()*2 - 1) * p.Jitter * float64(delay)
+		delay += time.Duration(delta)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay
+}
+
+func (p RetryPolicy) isRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay decides whether the failed attempt should be retried and how long to wait before it.
+func (p RetryPolicy) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || errors.Is(err, ErrorOffline) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusForbidden:
+			// csrf token is refreshed before the retry
+		case !p.isRetryableStatus(httpErr.StatusCode):
+			return 0, false
+		}
+		if httpErr.RetryAfter > 0 {
+			if p.MaxDelay > 0 && httpErr.RetryAfter > p.MaxDelay {
+				return 0, false
+			}
+			if httpErr.RetryAfter > delay {
+				delay = httpErr.RetryAfter
+			}
+		}
+		return delay, true
+	}
+
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) {
+		// the query itself is wrong, retrying won't help
+		return 0, false
+	}
+
+	// transport errors: connection resets, timeouts, broken bodies
+	return delay, true
+}
+
+// parseRetryAfter supports both delay-seconds and HTTP-date forms of the Retry-After header.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// notSent reports whether the failed attempt never reached the server or was rejected before processing.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests && httpErr.RetryAfter > 0
+}
+
+// doRequest executes the GraphQL request and returns the data field of the response.
+func (c *Client) doRequest(req *http.Request) ([]byte, error) {
+	return c.do(req, decodeGraphQLResponse, true)
+}
+
+// doRESTRequest executes the request to a non-GraphQL endpoint and returns the response body.
+func (c *Client) doRESTRequest(req *http.Request) ([]byte, error) {
+	return c.do(req, decodeRESTResponse, true)
+}
+
+// doNonIdempotentRESTRequest is doRESTRequest for requests with side effects, e.g. submissions, which are
+// retried only when the failed attempt was not sent to the server.
+func (c *Client) doNonIdempotentRESTRequest(req *http.Request) ([]byte, error) {
+	return c.do(req, decodeRESTResponse, false)
+}
+
+// do executes the request according to the retry policy.
+// Request body is re-created for every attempt, 403 responses trigger csrf token refresh before the next one.
+func (c *Client) do(req *http.Request, decode responseDecoder, idempotent bool) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := c.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := c.doAttempt(attemptReq, decode)
+		if err == nil {
+			return data, nil
+		}
+		if c.session != nil && errors.Is(err, ErrorUnauthorized) {
+			// session csrf token can't be refreshed without signing in again
+			return nil, fmt.Errorf("session rejected, sign in again: %w", err)
+		}
+
+		delay, retry := c.retry.retryDelay(attempt, err)
+		if !retry || (!idempotent && !notSent(err)) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+			return nil, err
+		}
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusForbidden {
+			c.invalidateCSRFToken()
+			if rErr := c.refreshCSRFToken(req.Context()); rErr != nil {
+				return nil, fmt.Errorf("%w (csrf refresh: %v)", err, rErr)
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, fmt.Errorf("http request: %w", req.Context().Err())
+		}
+	}
+}
+
+// attemptRequest returns the original request for the first attempt and its copy with a fresh body and
+// current csrf token for the following ones.
+func (c *Client) attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 1 {
+		return req, nil
+	}
+
+	attemptReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("re-create request body: %w", err)
+		}
+		attemptReq.Body = body
+	}
+
+	if req.Header.Get(csrfTokenHeader) != "" {
+		attemptReq.Header.Del("Cookie")
+		if err := c.addAuthHeaders(attemptReq); err != nil {
+			return nil, fmt.Errorf("add auth headers: %w", err)
+		}
+	}
+
+	return attemptReq, nil
+}
@@ -0,0 +1,186 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 10 * time.Millisecond
+	p.Jitter = 0
+	return p
+}
+
+func TestUnit_RetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.backoff(1)
+		assert.GreaterOrEqual(t, d, 50*time.Millisecond)
+		assert.LessOrEqual(t, d, 150*time.Millisecond)
+	}
+}
+
+func TestUnit_NotSent(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"dial error": {
+			err:      fmt.Errorf("http request: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			expected: true,
+		},
+		"connection reset": {
+			err: fmt.Errorf("http request: %w", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}),
+		},
+		"rate limited with retry-after": {
+			err:      &HTTPError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second},
+			expected: true,
+		},
+		"rate limited": {
+			err: &HTTPError{StatusCode: http.StatusTooManyRequests},
+		},
+		"server error": {
+			err: &HTTPError{StatusCode: http.StatusBadGateway},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, notSent(test.err))
+		})
+	}
+}
+
+func TestUnit_ParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		header   string
+		expected time.Duration
+	}{
+		"empty":       {header: "", expected: 0},
+		"seconds":     {header: "3", expected: 3 * time.Second},
+		"negative":    {header: "-3", expected: 0},
+		"http date":   {header: now.Add(time.Minute).Format(http.TimeFormat), expected: time.Minute},
+		"date passed": {header: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+		"garbage":     {header: "soon", expected: 0},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseRetryAfter(test.header, now))
+		})
+	}
+}
+
+func TestUnit_DoRequestRetry(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+	s.api.retry = testRetryPolicy()
+	s.api.csrf = &http.Cookie{Name: csrfTokenCookie, Value: "old_token"}
+
+	newRequest := func() *http.Request {
+		req, err := s.api.newRequest(context.Background(), dailyProblemQuery, nil)
+		assert.NoError(t, err)
+		return req
+	}
+	expectBody := func(response *http.Response, csrf string) func(req *http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Contains(t, string(body), "questionOfToday")
+			assert.Equal(t, csrf, req.Header.Get(csrfTokenHeader))
+			return response, nil
+		}
+	}
+
+	t.Run("server errors then success", func(t *testing.T) {
+		gomock.InOrder(
+			s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(
+				expectBody(newJSONResponse(http.StatusServiceUnavailable, ""), "old_token")),
+			s.httpCli.EXPECT().Do(gomock.Any()).Return(nil, fmt.Errorf("connection reset by peer")),
+			s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(
+				expectBody(newJSONResponse(http.StatusOK, `{"data":123}`), "old_token")),
+		)
+
+		data, err := s.api.doRequest(newRequest())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("123"), data)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusBadGateway, ""), nil).Times(3)
+
+		_, err := s.api.doRequest(newRequest())
+		assert.ErrorIs(t, err, ErrorUnavailable)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusOK, `{"errors":[{"message":"bad query"}]}`), nil)
+
+		_, err := s.api.doRequest(newRequest())
+		assert.EqualError(t, err, "graphql: bad query")
+	})
+
+	t.Run("retry after too long", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		recorder.Header().Set("Retry-After", "60")
+		recorder.WriteHeader(http.StatusTooManyRequests)
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(recorder.Result(), nil)
+
+		_, err := s.api.doRequest(newRequest())
+		assert.ErrorIs(t, err, ErrorRateLimited)
+	})
+
+	t.Run("forbidden refreshes csrf token", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		http.SetCookie(recorder, &http.Cookie{Name: csrfTokenCookie, Value: "new_token"})
+
+		gomock.InOrder(
+			s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(
+				expectBody(newJSONResponse(http.StatusForbidden, ""), "old_token")),
+			s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, http.MethodGet, req.Method)
+				return recorder.Result(), nil
+			}),
+			s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(
+				expectBody(newJSONResponse(http.StatusOK, `{"data":123}`), "new_token")),
+		)
+
+		data, err := s.api.doRequest(newRequest())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("123"), data)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		s.api.retry.BaseDelay = time.Hour
+		s.api.retry.MaxDelay = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := s.api.newRequest(ctx, dailyProblemQuery, nil)
+		assert.NoError(t, err)
+
+		s.httpCli.EXPECT().Do(gomock.Any()).Return(newJSONResponse(http.StatusServiceUnavailable, ""), nil)
+
+		_, err = s.api.doRequest(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
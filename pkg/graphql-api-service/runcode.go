@@ -0,0 +1,153 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	interpretPathTemplate = "/problems/%s/interpret_solution/"
+)
+
+type (
+	// RunResult is the judge verdict for a solution run against custom or example input.
+	RunResult struct {
+		InterpretID string
+		StatusCode  int    // see StatusCode* constants
+		Status      string // human readable status, e.g. "Accepted"
+		Correct     bool   // all outputs match expected answers
+
+		Runtime string
+		Memory  string
+		Cases   []RunCase
+
+		CompileError string
+		RuntimeError string
+	}
+
+	RunCase struct {
+		Input    string // input lines of the case
+		Output   string
+		Expected string
+		Stdout   string
+		Correct  bool
+	}
+
+	interpretRequest struct {
+		DataInput  string `json:"data_input"`
+		Lang       string `json:"lang"`
+		QuestionID string `json:"question_id"`
+		TypedCode  string `json:"typed_code"`
+	}
+
+	interpretResponse struct {
+		InterpretID string `json:"interpret_id"`
+	}
+)
+
+// RunCode runs the solution against input without submitting it, example test cases of the problem
+// are used if input is empty. The client must have a session.
+func (c *Client) RunCode(ctx context.Context, titleSlug, langSlug, code, input string) (RunResult, error) {
+	if err := c.requireSession(); err != nil {
+		return RunResult{}, err
+	}
+
+	question, err := c.judgeQuestion(ctx, titleSlug)
+	if err != nil {
+		return RunResult{}, wrapAPIError(err, "get question id")
+	}
+	if input == "" {
+		input = question.QuestionData.ExampleTestcases
+	}
+
+	referer := c.baseURL + fmt.Sprintf(problemRefererTemplate, titleSlug)
+	req, err := c.newRESTRequest(ctx, http.MethodPost, fmt.Sprintf(interpretPathTemplate, titleSlug), interpretRequest{
+		DataInput:  input,
+		Lang:       langSlug,
+		QuestionID: question.QuestionData.QuestionID,
+		TypedCode:  code,
+	})
+	if err != nil {
+		return RunResult{}, wrapAPIError(err, "init request")
+	}
+	c.addRefererHeader(req, referer)
+
+	body, err := c.doNonIdempotentRESTRequest(req)
+	if err != nil {
+		return RunResult{}, wrapAPIError(err, "run code")
+	}
+
+	parsedResponse := &interpretResponse{}
+	if err = json.Unmarshal(body, parsedResponse); err != nil || parsedResponse.InterpretID == "" {
+		return RunResult{}, fmt.Errorf("%w: unexpected interpret response: %s", ErrorSystem, body)
+	}
+
+	check, err := c.pollJudge(ctx, parsedResponse.InterpretID, referer)
+	if err != nil {
+		return RunResult{}, wrapAPIError(err, "wait for judge result")
+	}
+
+	return runResultFromCheck(parsedResponse.InterpretID, input, check), nil
+}
+
+func runResultFromCheck(id, input string, check *checkResponse) RunResult {
+	r := RunResult{
+		InterpretID:  id,
+		StatusCode:   check.StatusCode,
+		Status:       check.StatusMsg,
+		Correct:      check.CorrectAnswer,
+		Runtime:      check.StatusRuntime,
+		Memory:       check.StatusMemory,
+		CompileError: check.FullCompileError,
+		RuntimeError: check.FullRuntimeError,
+	}
+	if r.CompileError == "" {
+		r.CompileError = check.CompileError
+	}
+	if r.RuntimeError == "" {
+		r.RuntimeError = check.RuntimeError
+	}
+
+	inputs := splitRunInput(input, len(check.CodeAnswer))
+	r.Cases = make([]RunCase, 0, len(check.CodeAnswer))
+	for i, output := range check.CodeAnswer {
+		rc := RunCase{
+			Output: output,
+		}
+		if i < len(inputs) {
+			rc.Input = inputs[i]
+		}
+		if i < len(check.ExpectedCodeAnswer) {
+			rc.Expected = check.ExpectedCodeAnswer[i]
+		}
+		if i < len(check.StdOutputList) {
+			rc.Stdout = check.StdOutputList[i]
+		}
+		if i < len(check.CompareResult) {
+			rc.Correct = check.CompareResult[i] == '1'
+		} else {
+			rc.Correct = rc.Expected == rc.Output
+		}
+		r.Cases = append(r.Cases, rc)
+	}
+
+	return r
+}
+
+// splitRunInput splits input lines evenly between cases, every case takes one line per function parameter.
+func splitRunInput(input string, cases int) []string {
+	lines := strings.Split(strings.TrimRight(input, "\n"), "\n")
+	if cases == 0 || len(lines)%cases != 0 {
+		return nil
+	}
+
+	perCase := len(lines) / cases
+	inputs := make([]string, 0, cases)
+	for i := 0; i < len(lines); i += perCase {
+		inputs = append(inputs, strings.Join(lines[i:i+perCase], "\n"))
+	}
+	return inputs
+}
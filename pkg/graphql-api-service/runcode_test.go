@@ -0,0 +1,98 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_SplitRunInput(t *testing.T) {
+	assert.Equal(t, []string{"[2,7]\n9", "[3,2,4]\n6"}, splitRunInput("[2,7]\n9\n[3,2,4]\n6\n", 2))
+	assert.Equal(t, []string{"1", "2", "3"}, splitRunInput("1\n2\n3", 3))
+	assert.Nil(t, splitRunInput("1\n2\n3", 2))
+	assert.Nil(t, splitRunInput("1", 0))
+}
+
+func TestUnit_RunCode(t *testing.T) {
+	var dataInput string
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, `{"data":{"questionData":{"questionId":"1","exampleTestcases":"[2,7,11,15]\n9\n[3,2,4]\n6"}}}`)
+		},
+		"/problems/two-sum/interpret_solution/": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			payload := interpretRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, "1", payload.QuestionID)
+			assert.Equal(t, LangSlugGolang, payload.Lang)
+			dataInput = payload.DataInput
+
+			writeJSON(t, w, `{"interpret_id":"runcode_1","test_case":"[2,7,11,15]\n9"}`)
+		},
+		"/submissions/detail/runcode_1/check/": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, `{"state":"SUCCESS","status_code":10,"status_msg":"Accepted","run_success":true,`+
+				`"status_runtime":"0 ms","status_memory":"3.1 MB","correct_answer":false,"compare_result":"10",`+
+				`"code_answer":["[0,1]","[0,2]"],"expected_code_answer":["[0,1]","[1,2]"],`+
+				`"std_output_list":["","debug\n",""],"code_output":["debug"]}`)
+		},
+	})
+
+	r, err := c.RunCode(context.Background(), "two-sum", LangSlugGolang, "func twoSum() {}", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "[2,7,11,15]\n9\n[3,2,4]\n6", dataInput)
+	assert.Equal(t, RunResult{
+		InterpretID: "runcode_1",
+		StatusCode:  StatusCodeAccepted,
+		Status:      "Accepted",
+		Correct:     false,
+		Runtime:     "0 ms",
+		Memory:      "3.1 MB",
+		Cases: []RunCase{
+			{Input: "[2,7,11,15]\n9", Output: "[0,1]", Expected: "[0,1]", Stdout: "", Correct: true},
+			{Input: "[3,2,4]\n6", Output: "[0,2]", Expected: "[1,2]", Stdout: "debug\n", Correct: false},
+		},
+	}, r)
+
+	_, err = c.RunCode(context.Background(), "two-sum", LangSlugGolang, "func twoSum() {}", "[1,2]\n3")
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,2]\n3", dataInput)
+}
+
+func TestUnit_RunCodeCompileError(t *testing.T) {
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": questionIDHandler(t),
+		"/problems/two-sum/interpret_solution/": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, `{"interpret_id":"runcode_2"}`)
+		},
+		"/submissions/detail/runcode_2/check/": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, `{"state":"SUCCESS","status_code":20,"status_msg":"Compile Error","run_success":false,`+
+				`"compile_error":"Line 1: undefined: x","code_answer":[]}`)
+		},
+	})
+
+	r, err := c.RunCode(context.Background(), "two-sum", LangSlugGolang, "x", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCodeCompileError, r.StatusCode)
+	assert.Equal(t, "Line 1: undefined: x", r.CompileError)
+	assert.Empty(t, r.Cases)
+}
+
+func TestUnit_RunCodeNotRetried(t *testing.T) {
+	var posts int32
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": questionIDHandler(t),
+		"/problems/two-sum/interpret_solution/": func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&posts, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	})
+	c.retry = testRetryPolicy()
+
+	_, err := c.RunCode(context.Background(), "two-sum", LangSlugGolang, "x", "1")
+	assert.ErrorIs(t, err, ErrorUnavailable)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&posts))
+}
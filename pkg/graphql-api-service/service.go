@@ -1,155 +1,312 @@
 package graphqlapiservice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
 
 const (
 	refreshCooldown = 1 * time.Hour
+	defaultTimeout  = 10 * time.Second
 )
 
 var (
 	ErrorProblemNotFound = errors.New("problem not found")
 	ErrorSystem          = errors.New("system error")
+	ErrorUnauthorized    = errors.New("unauthorized")
+	ErrorRateLimited     = errors.New("rate limited")
+	ErrorUnavailable     = errors.New("service unavailable")
+
+	ErrorSubmissionNotFound = errors.New("submission not found")
+
+	// ErrorOffline is returned by offline clients for anything that is not cached, see WithOffline.
+	ErrorOffline = errors.New("offline")
+
+	// ErrorInvalidSnapshot is returned by ImportSnapshot for corrupt, truncated or incompatible snapshots.
+	ErrorInvalidSnapshot = errors.New("invalid snapshot")
+
+	// ErrorCacheFull is returned by ImportSnapshot when the cache can't keep all problems of the snapshot.
+	ErrorCacheFull = errors.New("cache full")
 )
 
 type (
 	Client struct {
-		cli httpClient
+		cli       httpClient
+		retry     RetryPolicy
+		limiter   *rateLimiter
+		timeout   time.Duration // of the default http client
+		site      *siteConfig
+		baseURL   string
+		userAgent string
+		logger    *log.Logger
+
+		pollInterval time.Duration // of judge results
+		pollTimeout  time.Duration
+		batchSize    int // problems requested at once by GetProblemsByTitleSlugs
 
-		mu              sync.RWMutex
-		csrf            *http.Cookie
-		problemIDMap    map[int]string    // id => titleSlug
-		problemTitleMap map[string]string // normalized title => titleSlug
-		problemCache    cache
+		session  *Session // immutable after NewAPIClient
+		offline  bool
+		prefetch *PrefetchConfig // nil unless enabled
 
-		wg    sync.WaitGroup
-		close chan struct{}
+		mu           sync.RWMutex
+		csrf         *http.Cookie
+		problemIndex *problemIndex // swapped as a whole on refresh
+		indexReady   chan struct{} // closed once the first refresh of Run is done, nil before Run
+		problemCache Cache
+		flights      flightGroup // concurrent fetches of the same data
+
+		revalidations  sync.WaitGroup  // of stale problems served from the cache
+		background     context.Context // of revalidations, cancelled by Stop
+		stopBackground context.CancelFunc
+
+		wg     sync.WaitGroup
+		cancel context.CancelFunc
 	}
 )
 
-func NewAPIClient() (*Client, error) {
+var _ LeetCodeAPIClient = (*Client)(nil)
+
+func NewAPIClient(opts ...Option) (*Client, error) {
 	c := &Client{
-		cli: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		problemCache: newCache(),
+		timeout:      defaultTimeout,
+		pollInterval: defaultPollInterval,
+		pollTimeout:  defaultPollTimeout,
+		batchSize:    defaultBatchSize,
+		retry:        DefaultRetryPolicy(),
+		limiter:      newRateLimiter(defaultRateLimit, defaultRateBurst),
+		site:         sites[SiteGlobal],
+		logger:       log.Default(),
+		problemCache: NewLRUCache(DefaultCacheConfig()),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if sites[c.site.site] != c.site {
+		return nil, fmt.Errorf("unknown site: %q", c.site.site)
+	}
+
+	if c.cli == nil {
+		c.cli = &http.Client{
+			Timeout: c.timeout,
+		}
+	}
+
+	if c.baseURL == "" {
+		c.baseURL = c.site.baseURL
+	}
+	if c.session != nil {
+		if err := c.session.validate(); err != nil {
+			return nil, fmt.Errorf("invalid session: %w", err)
+		}
+		c.csrf = &http.Cookie{Name: csrfTokenCookie, Value: c.session.CSRFToken}
 	}
 
+	baseURL, err := url.Parse(c.baseURL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base url: %q", c.baseURL)
+	}
+	c.baseURL = strings.TrimRight(c.baseURL, "/")
+	c.background, c.stopBackground = context.WithCancel(context.Background())
+
 	return c, nil
 }
 
 func (c *Client) GetProblemByTitle(title string) (Problem, error) {
-	if c.problemTitleMap == nil {
-		return Problem{}, fmt.Errorf("%w: problem title map is not initialized", ErrorSystem)
+	return c.GetProblemByTitleContext(context.Background(), title)
+}
+
+// GetProblemByTitleContext looks the problem up ignoring case, punctuation and whitespace, title slugs are accepted as well.
+func (c *Client) GetProblemByTitleContext(ctx context.Context, title string) (Problem, error) {
+	idx, err := c.lookupIndex(ctx)
+	if err != nil {
+		return Problem{}, err
 	}
-	c.mu.RLock()
-	titleSlug, ok := c.problemTitleMap[title]
-	c.mu.RUnlock()
+	titleSlug, ok := idx.titleSlugByTitle(title)
 	if !ok {
 		return Problem{}, ErrorProblemNotFound
 	}
 
-	return c.GetProblemByTitleSlug(titleSlug)
+	return c.GetProblemByTitleSlugContext(ctx, titleSlug)
 }
 
 func (c *Client) GetProblemByID(id int) (Problem, error) {
-	if c.problemIDMap == nil {
-		return Problem{}, fmt.Errorf("%w: problem title map is not initialized", ErrorSystem)
+	return c.GetProblemByIDContext(context.Background(), id)
+}
+
+// GetProblemByIDContext looks the problem up by its frontend id.
+func (c *Client) GetProblemByIDContext(ctx context.Context, id int) (Problem, error) {
+	idx, err := c.lookupIndex(ctx)
+	if err != nil {
+		return Problem{}, err
 	}
-	c.mu.RLock()
-	titleSlug, ok := c.problemIDMap[id]
-	c.mu.RUnlock()
+	titleSlug, ok := idx.titleSlugByID(id)
 	if !ok {
 		return Problem{}, ErrorProblemNotFound
 	}
 
-	return c.GetProblemByTitleSlug(titleSlug)
+	return c.GetProblemByTitleSlugContext(ctx, titleSlug)
 }
 
 func (c *Client) GetProblemByTitleSlug(titleSlug string) (Problem, error) {
-	if p, cacheHit := c.problemCache.get(titleSlug); cacheHit {
-		return p, nil
-	}
+	return c.GetProblemByTitleSlugContext(context.Background(), titleSlug)
+}
 
-	data, err := c.getProblemDataByTitleSlug(titleSlug)
-	if err != nil {
-		return Problem{}, fmt.Errorf("%w: get problem data from API", ErrorSystem)
+// GetProblemByTitleSlugContext returns the cached problem or requests it, concurrent requests
+// of the same problem share a single API call. Expired problems still kept by the cache are returned
+// right away and requested again in the background, see CacheConfig.StaleTTL.
+func (c *Client) GetProblemByTitleSlugContext(ctx context.Context, titleSlug string) (Problem, error) {
+	if c.offline {
+		return c.offlineProblem(titleSlug)
 	}
-
-	problem, err := externalProblemFromProblemData(data)
-	if err != nil {
-		return Problem{}, fmt.Errorf("%w: ", ErrorSystem)
+	if p, cacheHit := c.problemCache.Get(titleSlug); cacheHit {
+		return p, nil
+	}
+	if p, ok := c.staleProblem(titleSlug); ok {
+		c.revalidate(titleSlug)
+		return p, nil
 	}
-	c.problemCache.add(&problem)
 
-	return problem, nil
+	return c.fetchProblem(ctx, titleSlug)
 }
 
 func (c *Client) GetDailyProblem() (Problem, error) {
-	titleSlug, err := c.getDailyProblemTitle()
-	if err != nil {
-		return Problem{}, fmt.Errorf("%w: get daily problem title", ErrorSystem)
-	}
-
-	if p, cacheHit := c.problemCache.get(titleSlug); cacheHit {
-		return p, nil
-	}
+	return c.GetDailyProblemContext(context.Background())
+}
 
-	data, err := c.getProblemDataByTitleSlug(titleSlug)
-	if err != nil {
-		return Problem{}, fmt.Errorf("%w:: get problem data from API", ErrorSystem)
+func (c *Client) GetDailyProblemContext(ctx context.Context) (Problem, error) {
+	if c.offline {
+		titleSlug, err := c.offlineDailyProblemTitle(time.Now())
+		if err != nil {
+			return Problem{}, err
+		}
+		return c.offlineProblem(titleSlug)
 	}
 
-	problem, err := externalProblemFromProblemData(data)
+	titleSlug, err := c.fetchDailyProblemTitle(ctx)
 	if err != nil {
-		return Problem{}, fmt.Errorf("%w: ", ErrorSystem)
+		return Problem{}, wrapAPIError(err, "get daily problem title")
 	}
-	c.problemCache.add(&problem)
+	c.recordDailyProblem(time.Now(), titleSlug)
 
-	return problem, nil
+	return c.GetProblemByTitleSlugContext(ctx, titleSlug)
 }
 
-func (c *Client) Run() {
-	log.Println("Running LeetCode GraphQL API Service")
+// Run starts background refreshing of the csrf token, problem index and cache cleanup and returns right away.
+// The first refresh starts immediately, lookups by title and id wait for it and fail with ErrorSystem
+// if it could not populate the index. Background goroutines stop once ctx is done or Stop is called.
+func (c *Client) Run(ctx context.Context) {
+	c.logger.Println("Running LeetCode GraphQL API Service")
 
-	c.problemCache.run()
+	ctx, cancel := context.WithCancel(ctx)
+	ready := make(chan struct{})
+	c.mu.Lock()
+	c.cancel = cancel
+	c.indexReady = ready
+	c.mu.Unlock()
+
+	if r, ok := c.problemCache.(cacheRunner); ok {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			r.run(ctx)
+		}()
+	}
+
+	// offline clients have nothing to fetch
+	if c.prefetch != nil && !c.offline {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			// requests need the csrf token of the first refresh
+			select {
+			case <-ready:
+			case <-ctx.Done():
+				return
+			}
+			c.runPrefetcher(ctx, *c.prefetch)
+		}()
+	}
 
 	c.wg.Add(1)
 	go func() {
+		defer c.wg.Done()
+
+		// populate the index right away so lookups work before the first tick
+		c.refresh(ctx)
+		close(ready)
+
 		ticker := time.NewTicker(refreshCooldown)
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ticker.C:
-				err := c.refreshCSRFToken()
-				if err != nil {
-					log.Println("Error refreshing csrf token: %w", err)
-				}
-
-				err = c.refreshTitleSlugMaps()
-				if err != nil {
-					log.Println("Error refreshing problem title maps: %w", err)
-				}
-
-			case <-c.close:
-				c.problemIDMap = nil
-				c.problemTitleMap = nil
+				c.refresh(ctx)
+
+			case <-ctx.Done():
+				c.mu.Lock()
+				c.problemIndex = nil
+				c.mu.Unlock()
 				return
 			}
 		}
 	}()
 }
 
+func (c *Client) refresh(ctx context.Context) {
+	if c.offline {
+		if err := c.loadProblemIndex(); err != nil {
+			c.logger.Printf("Error loading problem index: %s", err)
+		}
+		return
+	}
+
+	if c.session != nil {
+		if u, err := c.CurrentUserContext(ctx); err != nil {
+			c.logger.Printf("Error validating session: %s", err)
+		} else {
+			c.logger.Printf("Signed in as %s", u.Username)
+		}
+	}
+
+	err := c.refreshCSRFToken(ctx)
+	if err != nil {
+		c.logger.Printf("Error refreshing csrf token: %s", err)
+	}
+
+	err = c.refreshTitleSlugMaps(ctx)
+	if err != nil {
+		c.logger.Printf("Error refreshing problem title maps: %s", err)
+	}
+}
+
+func (c *Client) index() *problemIndex {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.problemIndex
+}
+
+// Stop cancels background goroutines started by Run and revalidations of stale problems, and waits for them to exit.
+// Stale problems served afterwards are not revalidated.
 func (c *Client) Stop() {
-	log.Println("Stopping LeetCode GraphQL API Service")
-	c.problemCache.stop()
-	c.close <- struct{}{}
+	c.logger.Println("Stopping LeetCode GraphQL API Service")
+	c.mu.Lock()
+	cancel := c.cancel
+	// under the lock so that no revalidation starts after the wait below
+	c.stopBackground()
+	c.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
 	c.wg.Wait()
+	c.revalidations.Wait()
 }
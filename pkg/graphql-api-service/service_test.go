@@ -1 +1,86 @@
 package graphqlapiservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_RunStopsOnContextCancel(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+
+	// initial csrf refresh fails, problem list refresh is not attempted without a token
+	s.httpCli.EXPECT().Do(gomock.Any()).Return(nil, fmt.Errorf("test error"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.api.Run(ctx)
+
+	// lookups wait for the first refresh, which could not populate the index
+	_, err := s.api.GetProblemByIDContext(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrorSystem)
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.api.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("background goroutines did not stop")
+	}
+	assert.Nil(t, s.api.index())
+}
+
+func TestUnit_LookupWaitsForFirstRefresh(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+
+	release := make(chan struct{})
+	s.httpCli.EXPECT().Do(gomock.Any()).DoAndReturn(func(*http.Request) (*http.Response, error) {
+		<-release
+		return nil, fmt.Errorf("test error")
+	})
+
+	s.api.Run(context.Background())
+	defer s.api.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.api.GetProblemByTitleContext(ctx, "Two Sum")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	_, err = s.api.GetProblemByTitleContext(context.Background(), "Two Sum")
+	assert.ErrorIs(t, err, ErrorSystem)
+}
+
+func TestUnit_GetProblemByTitleContext(t *testing.T) {
+	s := newMockClient(t)
+	defer s.ctrl.Finish()
+
+	_, err := s.api.GetProblemByTitleContext(context.Background(), "Two Sum")
+	assert.ErrorIs(t, err, ErrorSystem)
+
+	s.api.problemIndex = newProblemIndex([]problemTitleMap{{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"}})
+	s.api.problemCache.Add(&Problem{ID: 1, Title: "Two Sum", TitleSlug: "two-sum"})
+
+	p, err := s.api.GetProblemByTitleContext(context.Background(), "two sum")
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+
+	p, err = s.api.GetProblemByIDContext(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+
+	_, err = s.api.GetProblemByIDContext(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrorProblemNotFound)
+}
@@ -0,0 +1,165 @@
+package graphqlapiservice
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sessionCookie = "LEETCODE_SESSION"
+
+	EnvSession   = "LEETCODE_SESSION"
+	EnvCSRFToken = "LEETCODE_CSRFTOKEN"
+
+	netscapeCookieFields = 7
+	httpOnlyPrefix       = "#HttpOnly_"
+)
+
+type (
+	// Session holds cookies of a signed-in browser session.
+	Session struct {
+		SessionID string // LEETCODE_SESSION cookie
+		CSRFToken string // csrftoken cookie issued along with the session
+	}
+
+	User struct {
+		Username   string
+		IsPremium  bool
+		IsSignedIn bool
+	}
+)
+
+func (s Session) validate() error {
+	if s.SessionID == "" {
+		return fmt.Errorf("%s is empty", sessionCookie)
+	}
+	if s.CSRFToken == "" {
+		return fmt.Errorf("%s is empty", csrfTokenCookie)
+	}
+	return nil
+}
+
+// SessionFromEnv reads session cookies from LEETCODE_SESSION and LEETCODE_CSRFTOKEN environment variables.
+func SessionFromEnv() (Session, error) {
+	s := Session{
+		SessionID: os.Getenv(EnvSession),
+		CSRFToken: os.Getenv(EnvCSRFToken),
+	}
+	if err := s.validate(); err != nil {
+		return Session{}, fmt.Errorf("session from env: %w", err)
+	}
+	return s, nil
+}
+
+// SessionFromCookieFile reads session cookies of the site from a cookie file in Netscape format,
+// as exported by browser extensions or written by curl.
+func SessionFromCookieFile(path string, site Site) (Session, error) {
+	f, err := os.Open(path) //nolint:gosec // path is provided by the user on purpose
+	if err != nil {
+		return Session{}, fmt.Errorf("open cookie file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // read only
+
+	s, err := parseCookieFile(f, string(site), time.Now())
+	if err != nil {
+		return Session{}, fmt.Errorf("cookie file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func parseCookieFile(r io.Reader, domain string, now time.Time) (Session, error) {
+	s := Session{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimPrefix(line, httpOnlyPrefix)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// domain, include subdomains, path, secure, expiration, name, value
+		fields := strings.Split(line, "\t")
+		if len(fields) != netscapeCookieFields {
+			continue
+		}
+		if strings.TrimPrefix(fields[0], ".") != domain {
+			continue
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expires > 0 && time.Unix(expires, 0).Before(now) {
+			continue
+		}
+
+		switch fields[5] {
+		case sessionCookie:
+			s.SessionID = fields[6]
+		case csrfTokenCookie:
+			s.CSRFToken = fields[6]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Session{}, fmt.Errorf("read: %w", err)
+	}
+
+	if err := s.validate(); err != nil {
+		return Session{}, fmt.Errorf("no valid cookies for %s: %w", domain, err)
+	}
+	return s, nil
+}
+
+func (c *Client) CurrentUser() (User, error) {
+	return c.CurrentUserContext(context.Background())
+}
+
+// CurrentUserContext validates the session with the userStatus query.
+// ErrorUnauthorized is returned if the client has a session that is no longer signed in.
+func (c *Client) CurrentUserContext(ctx context.Context) (User, error) {
+	req, err := c.newRequest(ctx, userStatusQuery, nil)
+	if err != nil {
+		return User{}, wrapAPIError(err, "init request")
+	}
+	c.addRefererHeader(req, c.baseURL+c.site.problemListReferer)
+
+	data, err := c.doRequest(req)
+	if err != nil {
+		return User{}, wrapAPIError(err, "query user status")
+	}
+
+	parsedResponse := &userStatusQueryData{}
+	if err = json.Unmarshal(data, parsedResponse); err != nil {
+		return User{}, fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+	}
+
+	u := User{}
+	if status := parsedResponse.UserStatus; status != nil {
+		u = User{
+			Username:   status.Username,
+			IsPremium:  status.IsPremium,
+			IsSignedIn: status.IsSignedIn,
+		}
+	}
+	if c.session != nil && !u.IsSignedIn {
+		return u, fmt.Errorf("%w: session expired, sign in again", ErrorUnauthorized)
+	}
+
+	return u, nil
+}
+
+// addAuthHeaders attaches csrf token and session cookie if the client has one.
+func (c *Client) addAuthHeaders(req *http.Request) error {
+	if err := c.addCSRFHeaders(req); err != nil {
+		return err
+	}
+	if c.session != nil {
+		req.AddCookie(&http.Cookie{Name: sessionCookie, Value: c.session.SessionID})
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_ParseCookieFile(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	testCases := map[string]struct {
+		content string
+		domain  string
+		session Session
+		err     bool
+	}{
+		"normal file": {
+			content: "# Netscape HTTP Cookie File\n" +
+				".leetcode.com\tTRUE\t/\tTRUE\t1800000000\tcsrftoken\tcsrf_value\n" +
+				"#HttpOnly_.leetcode.com\tTRUE\t/\tTRUE\t1800000000\tLEETCODE_SESSION\tsession_value\n" +
+				".leetcode.cn\tTRUE\t/\tTRUE\t1800000000\tcsrftoken\tother_site\n" +
+				"malformed line\n",
+			domain:  "leetcode.com",
+			session: Session{SessionID: "session_value", CSRFToken: "csrf_value"},
+		},
+		"session cookie without expiration": {
+			content: "leetcode.cn\tFALSE\t/\tTRUE\t0\tcsrftoken\tcsrf_value\n" +
+				"leetcode.cn\tFALSE\t/\tTRUE\t0\tLEETCODE_SESSION\tsession_value\n",
+			domain:  "leetcode.cn",
+			session: Session{SessionID: "session_value", CSRFToken: "csrf_value"},
+		},
+		"expired session": {
+			content: ".leetcode.com\tTRUE\t/\tTRUE\t1800000000\tcsrftoken\tcsrf_value\n" +
+				".leetcode.com\tTRUE\t/\tTRUE\t1600000000\tLEETCODE_SESSION\tsession_value\n",
+			domain: "leetcode.com",
+			err:    true,
+		},
+		"other site": {
+			content: ".leetcode.cn\tTRUE\t/\tTRUE\t1800000000\tcsrftoken\tcsrf_value\n" +
+				".leetcode.cn\tTRUE\t/\tTRUE\t1800000000\tLEETCODE_SESSION\tsession_value\n",
+			domain: "leetcode.com",
+			err:    true,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s, err := parseCookieFile(strings.NewReader(test.content), test.domain, now)
+			assert.Equal(t, test.session, s)
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnit_SessionSources(t *testing.T) {
+	t.Setenv(EnvSession, "session_value")
+	t.Setenv(EnvCSRFToken, "")
+	_, err := SessionFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv(EnvCSRFToken, "csrf_value")
+	s, err := SessionFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, Session{SessionID: "session_value", CSRFToken: "csrf_value"}, s)
+
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	err = os.WriteFile(path, []byte(
+		".leetcode.com\tTRUE\t/\tTRUE\t0\tcsrftoken\tcsrf_value\n"+
+			".leetcode.com\tTRUE\t/\tTRUE\t0\tLEETCODE_SESSION\tsession_value\n",
+	), 0o600)
+	assert.NoError(t, err)
+	s, err = SessionFromCookieFile(path, SiteGlobal)
+	assert.NoError(t, err)
+	assert.Equal(t, Session{SessionID: "session_value", CSRFToken: "csrf_value"}, s)
+
+	_, err = SessionFromCookieFile(filepath.Join(t.TempDir(), "missing.txt"), SiteGlobal)
+	assert.Error(t, err)
+
+	_, err = NewAPIClient(WithSession(Session{SessionID: "session_value"}))
+	assert.Error(t, err)
+}
+
+func TestUnit_CurrentUser(t *testing.T) {
+	signedIn := true
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("csrf token must not be refreshed with a session")
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		assert.NoError(t, err)
+		assert.Equal(t, "session_value", cookie.Value)
+		cookie, err = r.Cookie(csrfTokenCookie)
+		assert.NoError(t, err)
+		assert.Equal(t, "csrf_value", cookie.Value)
+		assert.Equal(t, "csrf_value", r.Header.Get(csrfTokenHeader))
+
+		if signedIn {
+			_, err = w.Write([]byte(`{"data":{"userStatus":{"username":"user","isSignedIn":true,"isPremium":true}}}`))
+		} else {
+			_, err = w.Write([]byte(`{"data":{"userStatus":{"username":"","isSignedIn":false,"isPremium":false}}}`))
+		}
+		assert.NoError(t, err)
+	})
+	mux.HandleFunc("/forbidden/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	session := Session{SessionID: "session_value", CSRFToken: "csrf_value"}
+	c, err := NewAPIClient(WithBaseURL(server.URL), WithSession(session))
+	assert.NoError(t, err)
+	assert.NoError(t, c.refreshCSRFToken(context.Background()))
+
+	u, err := c.CurrentUser()
+	assert.NoError(t, err)
+	assert.Equal(t, User{Username: "user", IsPremium: true, IsSignedIn: true}, u)
+
+	signedIn = false
+	_, err = c.CurrentUser()
+	assert.ErrorIs(t, err, ErrorUnauthorized)
+
+	c, err = NewAPIClient(WithBaseURL(server.URL+"/forbidden"), WithSession(session))
+	assert.NoError(t, err)
+	_, err = c.CurrentUser()
+	assert.ErrorIs(t, err, ErrorUnauthorized)
+	assert.Contains(t, err.Error(), "session rejected")
+}
@@ -0,0 +1,236 @@
+package graphqlapiservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	SiteGlobal Site = "leetcode.com"
+	SiteChina  Site = "leetcode.cn"
+
+	chinaBaseURL         = "https://leetcode.cn"
+	chinaGraphQLEndpoint = "/graphql/noj-go/"
+	chinaProblemReferer  = "/problemset/"
+
+	// Queries of leetcode.cn are hand-written and not validated as there is no snapshot of its schema.
+	// Their responses decode into the generated types of leetcode.com queries, plus translated* fields.
+
+	// leetcode.cn keeps both the original and the translated statement on the question
+	chinaProblemByTitleSlugQuery = `query questionData($titleSlug: String!) {
+	questionData: question(titleSlug: $titleSlug) {
+		questionId
+		questionFrontendId
+		title
+		titleSlug
+		translatedTitle
+		likes
+		dislikes
+		topicTags {
+			name
+			slug
+		}
+		similarQuestions
+		exampleTestcases
+		codeSnippets {
+			langSlug
+			code
+		}
+		content
+		translatedContent
+		isPaidOnly
+		canSeeQuestion
+		difficulty
+		categoryTitle
+		hints
+		metaData
+		stats
+		envInfo
+	}
+}
+`
+	chinaTotalProblemsQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput) {
+	problemsetQuestionList(
+		categorySlug: $categorySlug
+		filters: $filters
+	) {
+		total
+	}
+}
+`
+	chinaProblemListQuery = `query problemsetQuestionList($categorySlug: String, $filters: QuestionListFilterInput, $limit: Int) {
+	problemsetQuestionList(
+		categorySlug: $categorySlug
+		filters: $filters
+		limit: $limit
+	) {
+		questions {
+			title
+			titleSlug
+			translatedTitle: titleCn
+			frontendQuestionId
+		}
+	}
+}
+`
+	chinaProblemFilterQuery = `query problemsetQuestionList($categorySlug: String, $limit: Int, $skip: Int, $filters: QuestionListFilterInput) {
+	problemsetQuestionList(
+		categorySlug: $categorySlug
+		limit: $limit
+		skip: $skip
+		filters: $filters
+	) {
+		total
+		questions {
+			acRate
+			difficulty
+			frontendQuestionId
+			paidOnly
+			status
+			title
+			translatedTitle: titleCn
+			titleSlug
+			topicTags {
+				name
+				slug
+			}
+		}
+	}
+}
+`
+	chinaDailyProblemQuery = `query questionOfToday {
+	todayRecord {
+		question {
+			titleSlug
+		}
+	}
+}
+`
+)
+
+type (
+	// Site selects LeetCode backend the client talks to.
+	Site string
+
+	// siteConfig holds everything that differs between LeetCode backends. Queries alias
+	// fields so that responses decode into the same structs wherever schemas allow it.
+	siteConfig struct {
+		site               Site
+		baseURL            string
+		graphqlEndpoint    string
+		problemListReferer string
+
+		problemByTitleSlugQuery string
+		totalProblemsQuery      string
+		problemListQuery        string
+		problemFilterQuery      string
+		dailyProblemQuery       string
+
+		parseDailyProblemTitle func(data []byte) (string, error)
+		dailyRollover          *time.Location // daily problems change at its midnight
+	}
+
+	// translatedQuestionData decodes the fields leetcode.cn adds to problemByTitleSlugQuery, generated types
+	// only have the fields of leetcode.com.
+	translatedQuestionData struct {
+		QuestionData translatedQuestion `json:"questionData"`
+	}
+
+	translatedQuestion struct {
+		TranslatedTitle   string `json:"translatedTitle"`
+		TranslatedContent string `json:"translatedContent"`
+	}
+
+	// translatedQuestionList decodes titles leetcode.cn adds to problem lists.
+	translatedQuestionList struct {
+		ProblemsetQuestionList *struct {
+			Questions []struct {
+				TranslatedTitle string `json:"translatedTitle"`
+			} `json:"questions"`
+		} `json:"problemsetQuestionList"`
+	}
+
+	dailyRecordResponse struct {
+		Records []struct {
+			Question struct {
+				TitleSlug string `json:"titleSlug"`
+			} `json:"question"`
+		} `json:"todayRecord"`
+	}
+)
+
+// chinaDailyRollover is Beijing time, leetcode.cn changes its daily problem at midnight UTC+8.
+var chinaDailyRollover = time.FixedZone("UTC+8", 8*60*60)
+
+var sites = map[Site]*siteConfig{
+	SiteGlobal: {
+		site:                    SiteGlobal,
+		baseURL:                 defaultBaseURL,
+		graphqlEndpoint:         graphqlAPIEndpoint,
+		problemListReferer:      problemListReferer,
+		problemByTitleSlugQuery: problemByTitleSlugQuery,
+		totalProblemsQuery:      totalProblemsQuery,
+		problemListQuery:        problemListQuery,
+		problemFilterQuery:      problemFilterQuery,
+		dailyProblemQuery:       dailyProblemQuery,
+		parseDailyProblemTitle:  parseDailyChallengeTitle,
+		dailyRollover:           time.UTC,
+	},
+	SiteChina: {
+		site:                    SiteChina,
+		baseURL:                 chinaBaseURL,
+		graphqlEndpoint:         chinaGraphQLEndpoint,
+		problemListReferer:      chinaProblemReferer,
+		problemByTitleSlugQuery: chinaProblemByTitleSlugQuery,
+		totalProblemsQuery:      chinaTotalProblemsQuery,
+		problemListQuery:        chinaProblemListQuery,
+		problemFilterQuery:      chinaProblemFilterQuery,
+		dailyProblemQuery:       chinaDailyProblemQuery,
+		parseDailyProblemTitle:  parseDailyRecordTitle,
+		dailyRollover:           chinaDailyRollover,
+	},
+}
+
+func parseDailyChallengeTitle(data []byte) (string, error) {
+	parsedResponse := &dailyProblemQueryData{}
+	if err := json.Unmarshal(data, parsedResponse); err != nil {
+		return "", fmt.Errorf("response unmarshal: %w", err)
+	}
+	challenge := parsedResponse.ActiveDailyCodingChallengeQuestion
+	if challenge == nil || challenge.Question.TitleSlug == "" {
+		return "", fmt.Errorf("no active daily challenge: %w", ErrorProblemNotFound)
+	}
+
+	return challenge.Question.TitleSlug, nil
+}
+
+func parseDailyRecordTitle(data []byte) (string, error) {
+	parsedResponse := &dailyRecordResponse{}
+	if err := json.Unmarshal(data, parsedResponse); err != nil {
+		return "", fmt.Errorf("response unmarshal: %w", err)
+	}
+	if len(parsedResponse.Records) == 0 || parsedResponse.Records[0].Question.TitleSlug == "" {
+		return "", fmt.Errorf("no active daily challenge: %w", ErrorProblemNotFound)
+	}
+
+	return parsedResponse.Records[0].Question.TitleSlug, nil
+}
+
+// decodeTranslatedTitles returns translated titles of n questions of a problem list, empty for leetcode.com.
+func decodeTranslatedTitles(data []byte, n int) ([]string, error) {
+	list := &translatedQuestionList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, err
+	}
+
+	titles := make([]string, n)
+	if list.ProblemsetQuestionList != nil {
+		for i, q := range list.ProblemsetQuestionList.Questions {
+			if i < n {
+				titles[i] = q.TranslatedTitle
+			}
+		}
+	}
+	return titles, nil
+}
@@ -0,0 +1,110 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_SiteSelection(t *testing.T) {
+	c, err := NewAPIClient(WithSite(SiteChina))
+	assert.NoError(t, err)
+	assert.Equal(t, chinaBaseURL, c.baseURL)
+	assert.Same(t, sites[SiteChina], c.site)
+
+	c, err = NewAPIClient(WithBaseURL("http://localhost:8080"), WithSite(SiteChina))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", c.baseURL)
+
+	_, err = NewAPIClient(WithSite("leetcode.example"))
+	assert.EqualError(t, err, `unknown site: "leetcode.example"`)
+}
+
+func TestUnit_ParseDailyProblemTitle(t *testing.T) {
+	testCases := map[string]struct {
+		parse     func([]byte) (string, error)
+		data      string
+		titleSlug string
+		err       error
+	}{
+		"global": {
+			parse:     parseDailyChallengeTitle,
+			data:      `{"activeDailyCodingChallengeQuestion":{"question":{"titleSlug":"two-sum"}}}`,
+			titleSlug: "two-sum",
+		},
+		"global without challenge": {
+			parse: parseDailyChallengeTitle,
+			data:  `{"activeDailyCodingChallengeQuestion":null}`,
+			err:   ErrorProblemNotFound,
+		},
+		"china": {
+			parse:     parseDailyRecordTitle,
+			data:      `{"todayRecord":[{"question":{"titleSlug":"two-sum"}}]}`,
+			titleSlug: "two-sum",
+		},
+		"china without record": {
+			parse: parseDailyRecordTitle,
+			data:  `{"todayRecord":[]}`,
+			err:   ErrorProblemNotFound,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			titleSlug, err := test.parse([]byte(test.data))
+			assert.Equal(t, test.titleSlug, titleSlug)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUnit_ChinaSiteStandIn(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: csrfTokenCookie, Value: "token"})
+	})
+	mux.HandleFunc(chinaGraphQLEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		q := graphQLRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+
+		var response string
+		switch q.Query {
+		case chinaTotalProblemsQuery:
+			response = `{"data":{"problemsetQuestionList":{"total":1}}}`
+		case chinaProblemListQuery:
+			response = `{"data":{"problemsetQuestionList":{"questions":[` +
+				`{"title":"Two Sum","titleSlug":"two-sum","translatedTitle":"两数之和","frontendQuestionId":"1"}]}}}`
+		case chinaDailyProblemQuery:
+			response = `{"data":{"todayRecord":[{"question":{"titleSlug":"two-sum"}}]}}`
+		default:
+			t.Errorf("unexpected query: %s", q.Query)
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := NewAPIClient(WithSite(SiteChina), WithBaseURL(server.URL))
+	assert.NoError(t, err)
+	c.problemCache.Add(&Problem{ID: 1, TitleSlug: "two-sum", TranslatedTitle: "两数之和"})
+
+	assert.NoError(t, c.refreshCSRFToken(context.Background()))
+	assert.NoError(t, c.refreshTitleSlugMaps(context.Background()))
+
+	p, err := c.GetProblemByTitle("两数之和")
+	assert.NoError(t, err)
+	assert.Equal(t, "两数之和", p.TranslatedTitle)
+
+	p, err = c.GetDailyProblem()
+	assert.NoError(t, err)
+	assert.Equal(t, "two-sum", p.TitleSlug)
+}
@@ -0,0 +1,240 @@
+package graphqlapiservice
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	snapshotVersion        = problemVersion // snapshots of older problems are rejected
+	snapshotChecksumPrefix = "sha256:"
+
+	snapshotRecordProblem  = "problem"
+	snapshotRecordIndex    = "index"
+	snapshotRecordManifest = "manifest"
+)
+
+type (
+	// SnapshotManifest describes a snapshot, it is written as its last line.
+	SnapshotManifest struct {
+		Version      int       `json:"version"`
+		Site         Site      `json:"site"`
+		FetchedAt    time.Time `json:"fetchedAt"`
+		Problems     int       `json:"problems"`
+		IndexEntries int       `json:"indexEntries"`
+		Checksum     string    `json:"checksum"` // of the uncompressed lines before the manifest
+	}
+
+	// snapshotRecord is a single line of a snapshot, kind tells which of the fields is set.
+	snapshotRecord struct {
+		Kind     string            `json:"kind"`
+		Problem  *Problem          `json:"problem,omitempty"`
+		Index    []problemTitleMap `json:"index,omitempty"`
+		Manifest *SnapshotManifest `json:"manifest,omitempty"`
+	}
+)
+
+// ExportSnapshot writes all problems matching the filter to w as gzip-compressed JSON Lines:
+// a line per problem, then the id and title index of the exported problems and the manifest.
+// Problems are fetched in batches, see GetProblemsByTitleSlugs, the first failure aborts the export.
+func (c *Client) ExportSnapshot(ctx context.Context, w io.Writer, filter ProblemFilter) (SnapshotManifest, error) {
+	manifest := SnapshotManifest{
+		Version:   snapshotVersion,
+		Site:      c.site.site,
+		FetchedAt: time.Now().UTC(),
+	}
+
+	zw := gzip.NewWriter(w)
+	checksum := sha256.New()
+	enc := json.NewEncoder(io.MultiWriter(zw, checksum))
+
+	var refs []problemTitleMap
+	batch := make([]string, 0, c.batchSize)
+	flush := func() error {
+		for _, r := range c.GetProblemsByTitleSlugs(ctx, batch) {
+			if r.Err != nil {
+				return fmt.Errorf("export problem %s: %w", r.TitleSlug, r.Err)
+			}
+			p := r.Problem
+			if err := enc.Encode(snapshotRecord{Kind: snapshotRecordProblem, Problem: &p}); err != nil {
+				return fmt.Errorf("write problem: %w", err)
+			}
+			manifest.Problems++
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	it := c.IterateProblems(filter)
+	for it.Next(ctx) {
+		s := it.Problem()
+		refs = append(refs, problemTitleMap{
+			Title:           s.Title,
+			TitleSlug:       s.TitleSlug,
+			ID:              s.FrontendID,
+			TranslatedTitle: s.TranslatedTitle,
+		})
+		batch = append(batch, s.TitleSlug)
+		if len(batch) >= c.batchSize {
+			if err := flush(); err != nil {
+				return SnapshotManifest{}, err
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return SnapshotManifest{}, err
+	}
+	if err := flush(); err != nil {
+		return SnapshotManifest{}, err
+	}
+
+	if err := enc.Encode(snapshotRecord{Kind: snapshotRecordIndex, Index: refs}); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("write index: %w", err)
+	}
+	manifest.IndexEntries = len(refs)
+	manifest.Checksum = snapshotChecksumPrefix + hex.EncodeToString(checksum.Sum(nil))
+
+	if err := json.NewEncoder(zw).Encode(snapshotRecord{Kind: snapshotRecordManifest, Manifest: &manifest}); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("write manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("close snapshot: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ImportSnapshot loads a snapshot written by ExportSnapshot into the cache and the problem index,
+// where it complements problems already known. Nothing is loaded unless the whole snapshot is valid,
+// otherwise ErrorInvalidSnapshot is returned.
+// Problems are cached without expiration as fetched at the time of the snapshot. Caches without room for all
+// of them, e.g. the default LRUCache for the whole catalog, keep what fits and ErrorCacheFull is returned,
+// use DiskCache to keep large snapshots.
+func (c *Client) ImportSnapshot(r io.Reader) (SnapshotManifest, error) {
+	manifest, lines, err := readSnapshot(r)
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("%w: %v", ErrorInvalidSnapshot, err)
+	}
+	if manifest.Site != c.site.site {
+		return SnapshotManifest{}, fmt.Errorf("%w: snapshot of %s, client uses %s", ErrorInvalidSnapshot, manifest.Site, c.site.site)
+	}
+
+	var problems []Problem
+	var refs []problemTitleMap
+	for i, line := range lines {
+		rec := &snapshotRecord{}
+		if err = json.Unmarshal(line, rec); err != nil {
+			return SnapshotManifest{}, fmt.Errorf("%w: line %d: %v", ErrorInvalidSnapshot, i+1, err)
+		}
+		switch {
+		case rec.Kind == snapshotRecordProblem && rec.Problem != nil:
+			problems = append(problems, *rec.Problem)
+		case rec.Kind == snapshotRecordIndex:
+			refs = append(refs, rec.Index...)
+		default:
+			return SnapshotManifest{}, fmt.Errorf("%w: line %d: unexpected %q record", ErrorInvalidSnapshot, i+1, rec.Kind)
+		}
+	}
+	if len(problems) != manifest.Problems || len(refs) != manifest.IndexEntries {
+		return SnapshotManifest{}, fmt.Errorf("%w: %d problems and %d index entries, manifest lists %d and %d",
+			ErrorInvalidSnapshot, len(problems), len(refs), manifest.Problems, manifest.IndexEntries)
+	}
+
+	frozen, ok := c.problemCache.(frozenCache)
+	if !ok {
+		return SnapshotManifest{}, fmt.Errorf("%w: the cache doesn't keep snapshots, see WithCache", ErrorCacheFull)
+	}
+	kept := 0
+	for i := range problems {
+		if err = frozen.addFrozen(&problems[i], manifest.FetchedAt); err == nil {
+			kept++
+		}
+	}
+	c.mergeProblemIndex(refs)
+
+	if kept < len(problems) {
+		return manifest, fmt.Errorf("%w: kept %d of %d problems", ErrorCacheFull, kept, len(problems))
+	}
+	return manifest, nil
+}
+
+// readSnapshot returns the manifest and the lines before it once they match its version and checksum.
+func readSnapshot(r io.Reader) (SnapshotManifest, [][]byte, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return SnapshotManifest{}, nil, fmt.Errorf("open: %w", err)
+	}
+	defer zr.Close() //nolint:errcheck // read errors are reported by ReadBytes
+
+	checksum := sha256.New()
+	br := bufio.NewReader(zr)
+	var lines [][]byte
+	var manifest *SnapshotManifest
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if manifest != nil {
+				return SnapshotManifest{}, nil, fmt.Errorf("data after manifest")
+			}
+			rec := &struct {
+				Kind     string            `json:"kind"`
+				Manifest *SnapshotManifest `json:"manifest"`
+			}{}
+			// only the manifest is decoded here, malformed lines are reported once the checksum matches
+			_ = json.Unmarshal(line, rec)
+			if rec.Kind == snapshotRecordManifest && rec.Manifest != nil {
+				manifest = rec.Manifest
+			} else {
+				_, _ = checksum.Write(line)
+				lines = append(lines, line)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return SnapshotManifest{}, nil, fmt.Errorf("read: %w", err)
+		}
+	}
+
+	if manifest == nil {
+		return SnapshotManifest{}, nil, fmt.Errorf("no manifest, snapshot is truncated")
+	}
+	if manifest.Version != snapshotVersion {
+		return SnapshotManifest{}, nil, fmt.Errorf("unsupported version %d", manifest.Version)
+	}
+	if sum := snapshotChecksumPrefix + hex.EncodeToString(checksum.Sum(nil)); sum != manifest.Checksum {
+		return SnapshotManifest{}, nil, fmt.Errorf("checksum mismatch")
+	}
+
+	return *manifest, lines, nil
+}
+
+// mergeProblemIndex adds problems missing from the index and keeps the result for offline use.
+// Known problems win ids and titles claimed by both.
+func (c *Client) mergeProblemIndex(refs []problemTitleMap) {
+	c.mu.Lock()
+	merged := refs
+	if c.problemIndex != nil {
+		merged = make([]problemTitleMap, 0, len(refs)+c.problemIndex.size())
+		for _, ref := range refs {
+			if _, ok := c.problemIndex.reference(ref.TitleSlug); !ok {
+				merged = append(merged, ref)
+			}
+		}
+		// later references override earlier ones in the index
+		merged = append(merged, c.problemIndex.refs()...)
+	}
+	c.problemIndex = newProblemIndex(merged)
+	c.mu.Unlock()
+
+	c.saveProblemIndex(merged)
+}
@@ -0,0 +1,237 @@
+package graphqlapiservice
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// snapshotHandler serves a list of total problems and batches of their details.
+func snapshotHandler(t *testing.T, total int) http.HandlerFunc {
+	list := problemListHandler(t, total, new([]queryVariables))
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		q := graphQLRequest{}
+		assert.NoError(t, json.Unmarshal(body, &q))
+
+		if !strings.HasPrefix(q.Query, "query questionDataBatch(") {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			list(w, r)
+			return
+		}
+
+		data := make([]string, 0, len(q.Variables))
+		for i := 0; i < len(q.Variables); i++ {
+			slug := q.Variables[fmt.Sprintf("titleSlug%d", i)].(string)
+			id := strings.TrimPrefix(slug, "problem-")
+			data = append(data, fmt.Sprintf(
+				`"q%d":{"questionId":"%s","questionFrontendId":"%s","title":"Problem %s","titleSlug":"%s","hints":["hint"]}`,
+				i, id, id, id, slug,
+			))
+		}
+		writeJSON(t, w, fmt.Sprintf(`{"data":{%s}}`, strings.Join(data, ",")))
+	}
+}
+
+func exportTestSnapshot(t *testing.T, total int) []byte {
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		graphqlAPIEndpoint: snapshotHandler(t, total),
+	})
+	c.batchSize = 2
+
+	var buf bytes.Buffer
+	manifest, err := c.ExportSnapshot(context.Background(), &buf, ProblemFilter{PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, snapshotVersion, manifest.Version)
+	assert.Equal(t, SiteGlobal, manifest.Site)
+	assert.Equal(t, total, manifest.Problems)
+	assert.Equal(t, total, manifest.IndexEntries)
+	assert.True(t, strings.HasPrefix(manifest.Checksum, snapshotChecksumPrefix))
+	return buf.Bytes()
+}
+
+// rewriteSnapshot decompresses the snapshot, lets fn change its lines and compresses it again.
+func rewriteSnapshot(t *testing.T, snapshot []byte, fn func(lines []string) []string) []byte {
+	zr, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	data, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	lines := fn(strings.SplitAfter(string(data), "\n"))
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write([]byte(strings.Join(lines, "")))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestUnit_Snapshot(t *testing.T) {
+	snapshot := exportTestSnapshot(t, 5)
+
+	api, err := NewAPIClient(WithLogger(nil), WithOffline())
+	if !assert.NoError(t, err) {
+		return
+	}
+	manifest, err := api.ImportSnapshot(bytes.NewReader(snapshot))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, manifest.Problems)
+
+	ctx := context.Background()
+	p, err := api.GetProblemByIDContext(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "problem-3", p.TitleSlug)
+	assert.Equal(t, []string{"hint"}, p.Hints)
+	p, err = api.GetProblemByTitleContext(ctx, "Problem 5")
+	assert.NoError(t, err)
+	assert.Equal(t, "problem-5", p.TitleSlug)
+
+	// problems already known are kept
+	api.problemIndex = newProblemIndex([]problemTitleMap{{Title: "Two Sum", TitleSlug: "two-sum", ID: "1"}})
+	_, err = api.ImportSnapshot(bytes.NewReader(snapshot))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, api.index().size())
+	titleSlug, ok := api.index().titleSlugByID(1)
+	assert.True(t, ok)
+	assert.Equal(t, "two-sum", titleSlug)
+}
+
+func TestUnit_ImportSnapshotCaches(t *testing.T) {
+	snapshot := exportTestSnapshot(t, 5)
+
+	newDiskCache := func() Cache {
+		disk, err := NewDiskCache(t.TempDir(), time.Minute)
+		assert.NoError(t, err)
+		return disk
+	}
+	smallLRU := func() *LRUCache {
+		return NewLRUCache(CacheConfig{MaxEntries: 3, TTL: time.Minute, StaleTTL: time.Minute})
+	}
+
+	tests := map[string]struct {
+		cache Cache
+		kept  int
+		err   error
+	}{
+		"disk":               {cache: newDiskCache(), kept: 5},
+		"lru":                {cache: NewLRUCache(DefaultCacheConfig()), kept: 5},
+		"full lru":           {cache: smallLRU(), kept: 3, err: ErrorCacheFull},
+		"full lru over disk": {cache: NewLayeredCache(smallLRU(), newDiskCache()), kept: 5},
+		"no cache":           {cache: nil, err: ErrorCacheFull},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api, err := NewAPIClient(WithLogger(nil), WithOffline(), WithCache(tc.cache))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			manifest, err := api.ImportSnapshot(bytes.NewReader(snapshot))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			kept := 0
+			entries, ok := api.problemCache.(staleCache)
+			for i := 1; ok && i <= 5; i++ {
+				e, ok := entries.Entry(fmt.Sprintf("problem-%d", i))
+				if !ok {
+					continue
+				}
+				kept++
+				assert.True(t, e.Expires.IsZero(), "imported problems never expire")
+				assert.True(t, manifest.FetchedAt.Equal(e.FetchedAt), "fetched with the snapshot")
+			}
+			assert.Equal(t, tc.kept, kept)
+		})
+	}
+}
+
+func TestUnit_ImportInvalidSnapshot(t *testing.T) {
+	snapshot := exportTestSnapshot(t, 3)
+
+	tests := map[string]struct {
+		snapshot []byte
+		site     Site
+	}{
+		"not gzip": {
+			snapshot: []byte("{}"),
+		},
+		"truncated": {
+			snapshot: snapshot[:len(snapshot)/2],
+		},
+		"no manifest": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				return lines[:len(lines)-2]
+			}),
+		},
+		"changed problem": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				lines[0] = strings.Replace(lines[0], "Problem 1", "Problem 7", 1)
+				return lines
+			}),
+		},
+		"dropped problem": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				return lines[1:]
+			}),
+		},
+		"data after manifest": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				return append(lines, lines[0])
+			}),
+		},
+		"unsupported version": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				last := len(lines) - 2
+				lines[last] = strings.Replace(lines[last], fmt.Sprintf(`"version":%d`, snapshotVersion), fmt.Sprintf(`"version":%d`, snapshotVersion+1), 1)
+				return lines
+			}),
+		},
+		"older version": {
+			snapshot: rewriteSnapshot(t, snapshot, func(lines []string) []string {
+				last := len(lines) - 2
+				lines[last] = strings.Replace(lines[last], fmt.Sprintf(`"version":%d`, snapshotVersion), `"version":1`, 1)
+				return lines
+			}),
+		},
+		"other site": {
+			snapshot: snapshot,
+			site:     SiteChina,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			site := SiteGlobal
+			if tc.site != "" {
+				site = tc.site
+			}
+			api, err := NewAPIClient(WithLogger(nil), WithSite(site), WithOffline())
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = api.ImportSnapshot(bytes.NewReader(tc.snapshot))
+			assert.ErrorIs(t, err, ErrorInvalidSnapshot)
+			assert.Nil(t, api.index(), "nothing is loaded")
+			_, ok := api.problemCache.Get("problem-1")
+			assert.False(t, ok, "nothing is loaded")
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultSubmissionPageSize = 20
+	submissionsReferer        = "/submissions/"
+
+	variableOffset       = "offset"
+	variableLastKey      = "lastKey"
+	variableQuestionSlug = "questionSlug"
+	variableSubmissionID = "submissionId"
+)
+
+type (
+	// SubmissionFilter selects submissions of the signed-in user, zero value lists all of them.
+	SubmissionFilter struct {
+		TitleSlug    string // submissions of a single problem
+		AcceptedOnly bool
+		Limit        int // max number of submissions returned, 0 for no limit
+		PageSize     int // submissions requested at once, defaults to 20
+	}
+
+	Submission struct {
+		ID         string
+		Title      string
+		TitleSlug  string
+		StatusCode int    // see StatusCode* constants
+		Status     string // human readable status, e.g. "Accepted"
+		Lang       string // langSlug
+		LangName   string
+		Runtime    string
+		Memory     string
+		Timestamp  time.Time
+		Code       string // set by GetSubmission only
+	}
+
+	// SubmissionIterator streams submissions page by page, every page is a separate rate limited request.
+	SubmissionIterator struct {
+		c      *Client
+		filter SubmissionFilter
+
+		page     []Submission
+		offset   int
+		lastKey  string
+		hasNext  bool
+		returned int
+
+		current Submission
+		err     error
+	}
+)
+
+// unixTime converts seconds since epoch of submission details, zero is unknown.
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
+// parseUnixTime converts seconds since epoch of the submission list, which come as a string.
+func parseUnixTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp: %w", err)
+	}
+	return unixTime(seconds), nil
+}
+
+// ListSubmissions returns all submissions of the signed-in user matching the filter, newest first.
+func (c *Client) ListSubmissions(ctx context.Context, filter SubmissionFilter) ([]Submission, error) {
+	var submissions []Submission
+	it := c.IterateSubmissions(filter)
+	for it.Next(ctx) {
+		submissions = append(submissions, it.Submission())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// IterateSubmissions returns an iterator over submissions of the signed-in user matching the filter, newest first.
+func (c *Client) IterateSubmissions(filter SubmissionFilter) *SubmissionIterator {
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultSubmissionPageSize
+	}
+	return &SubmissionIterator{
+		c:       c,
+		filter:  filter,
+		hasNext: true,
+	}
+}
+
+// Next advances the iterator, fetching the next page when needed. It returns false once
+// submissions are exhausted, the limit is reached or an error occurs, see Err.
+func (it *SubmissionIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.filter.Limit > 0 && it.returned >= it.filter.Limit) {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if !it.hasNext {
+			return false
+		}
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	it.returned++
+	return true
+}
+
+func (it *SubmissionIterator) Submission() Submission {
+	return it.current
+}
+
+func (it *SubmissionIterator) Err() error {
+	return it.err
+}
+
+func (it *SubmissionIterator) fetch(ctx context.Context) error {
+	if err := it.c.requireSession(); err != nil {
+		return err
+	}
+
+	variables := map[string]interface{}{
+		variableOffset: it.offset,
+		variableLimit:  it.filter.PageSize,
+	}
+	if it.lastKey != "" {
+		variables[variableLastKey] = it.lastKey
+	}
+	if it.filter.TitleSlug != "" {
+		variables[variableQuestionSlug] = it.filter.TitleSlug
+	}
+
+	req, err := it.c.newRequest(ctx, submissionListQuery, variables)
+	if err != nil {
+		return wrapAPIError(err, "init request")
+	}
+	it.c.addRefererHeader(req, it.c.baseURL+submissionsReferer)
+
+	data, err := it.c.doRequest(req)
+	if err != nil {
+		return wrapAPIError(err, "query submission list")
+	}
+
+	parsedResponse := &submissionListQueryData{}
+	if err = json.Unmarshal(data, parsedResponse); err != nil {
+		return fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+	}
+	list := parsedResponse.SubmissionList
+	if list == nil {
+		return fmt.Errorf("%w: empty submission list", ErrorSystem)
+	}
+
+	it.offset += len(list.Submissions)
+	it.lastKey = list.LastKey
+	it.hasNext = list.HasNext && len(list.Submissions) > 0
+
+	for _, s := range list.Submissions {
+		if it.filter.AcceptedOnly && s.Status != StatusCodeAccepted {
+			continue
+		}
+		timestamp, err := parseUnixTime(s.Timestamp)
+		if err != nil {
+			return fmt.Errorf("%w: submission %s: %v", ErrorSystem, s.ID, err)
+		}
+		it.page = append(it.page, Submission{
+			ID:         s.ID,
+			Title:      s.Title,
+			TitleSlug:  s.TitleSlug,
+			StatusCode: s.Status,
+			Status:     s.StatusDisplay,
+			Lang:       s.Lang,
+			LangName:   s.LangName,
+			Runtime:    s.Runtime,
+			Memory:     s.Memory,
+			Timestamp:  timestamp,
+		})
+	}
+
+	return nil
+}
+
+// GetSubmission returns a submission of the signed-in user including its code.
+func (c *Client) GetSubmission(ctx context.Context, id string) (Submission, error) {
+	if err := c.requireSession(); err != nil {
+		return Submission{}, err
+	}
+	submissionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Submission{}, fmt.Errorf("%w: invalid submission id %q", ErrorSubmissionNotFound, id)
+	}
+
+	req, err := c.newRequest(ctx, submissionDetailsQuery, map[string]interface{}{
+		variableSubmissionID: submissionID,
+	})
+	if err != nil {
+		return Submission{}, wrapAPIError(err, "init request")
+	}
+	c.addRefererHeader(req, c.baseURL+submissionsReferer)
+
+	data, err := c.doRequest(req)
+	if err != nil {
+		return Submission{}, wrapAPIError(err, "query submission details")
+	}
+
+	parsedResponse := &submissionDetailsQueryData{}
+	if err = json.Unmarshal(data, parsedResponse); err != nil {
+		return Submission{}, fmt.Errorf("%w: response unmarshal: %v", ErrorSystem, err)
+	}
+	d := parsedResponse.SubmissionDetails
+	if d == nil {
+		return Submission{}, fmt.Errorf("%w: %s", ErrorSubmissionNotFound, id)
+	}
+
+	s := Submission{
+		ID:         id,
+		StatusCode: d.StatusCode,
+		Status:     statusMessages[d.StatusCode],
+		Runtime:    d.RuntimeDisplay,
+		Memory:     d.MemoryDisplay,
+		Timestamp:  unixTime(int64(d.Timestamp)),
+		Code:       d.Code,
+	}
+	if d.Question != nil {
+		s.Title, s.TitleSlug = d.Question.Title, d.Question.TitleSlug
+	}
+	if d.Lang != nil {
+		s.Lang, s.LangName = d.Lang.Name, d.Lang.VerboseName
+	}
+	return s, nil
+}
@@ -0,0 +1,143 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnit_UnixTimestamp(t *testing.T) {
+	ts, err := parseUnixTime("1690000000")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Unix(1690000000, 0).UTC(), ts)
+	ts, err = parseUnixTime("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Time{}, ts)
+	_, err = parseUnixTime("yesterday")
+	assert.Error(t, err)
+	assert.Equal(t, time.Time{}, unixTime(0))
+}
+
+func submissionListHandler(t *testing.T, requests *[]queryVariables) http.HandlerFunc {
+	pages := map[string]string{
+		"": `{"data":{"submissionList":{"lastKey":"key1","hasNext":true,"submissions":[` +
+			`{"id":"3","title":"Two Sum","titleSlug":"two-sum","status":10,"statusDisplay":"Accepted",` +
+			`"lang":"golang","langName":"Go","runtime":"4 ms","memory":"4.2 MB","timestamp":"1690000003"},` +
+			`{"id":"2","title":"Two Sum","titleSlug":"two-sum","status":11,"statusDisplay":"Wrong Answer",` +
+			`"lang":"golang","langName":"Go","runtime":"N/A","memory":"N/A","timestamp":"1690000002"}]}}}`,
+		"key1": `{"data":{"submissionList":{"lastKey":"key2","hasNext":false,"submissions":[` +
+			`{"id":"1","title":"Add Two Numbers","titleSlug":"add-two-numbers","status":10,"statusDisplay":"Accepted",` +
+			`"lang":"python3","langName":"Python3","runtime":"60 ms","memory":"14 MB","timestamp":"1690000001"}]}}}`,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := graphQLRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+		*requests = append(*requests, q.Variables)
+
+		lastKey, _ := q.Variables[variableLastKey].(string)
+		writeJSON(t, w, pages[lastKey])
+	}
+}
+
+func TestUnit_ListSubmissions(t *testing.T) {
+	var requests []queryVariables
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": submissionListHandler(t, &requests),
+	})
+
+	submissions, err := c.ListSubmissions(context.Background(), SubmissionFilter{PageSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, submissions, 3)
+	assert.Equal(t, Submission{
+		ID:         "3",
+		Title:      "Two Sum",
+		TitleSlug:  "two-sum",
+		StatusCode: StatusCodeAccepted,
+		Status:     "Accepted",
+		Lang:       "golang",
+		LangName:   "Go",
+		Runtime:    "4 ms",
+		Memory:     "4.2 MB",
+		Timestamp:  time.Unix(1690000003, 0).UTC(),
+	}, submissions[0])
+	assert.Equal(t, "1", submissions[2].ID)
+	assert.Equal(t, []queryVariables{
+		{variableOffset: float64(0), variableLimit: float64(2)},
+		{variableOffset: float64(2), variableLimit: float64(2), variableLastKey: "key1"},
+	}, requests)
+
+	requests = nil
+	submissions, err = c.ListSubmissions(context.Background(), SubmissionFilter{TitleSlug: "two-sum", AcceptedOnly: true, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, submissions, 1)
+	assert.Equal(t, "3", submissions[0].ID)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "two-sum", requests[0][variableQuestionSlug])
+}
+
+func TestUnit_SubmissionIterator(t *testing.T) {
+	var requests []queryVariables
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": submissionListHandler(t, &requests),
+	})
+
+	it := c.IterateSubmissions(SubmissionFilter{PageSize: 2})
+	assert.True(t, it.Next(context.Background()))
+	assert.Equal(t, "3", it.Submission().ID)
+	assert.True(t, it.Next(context.Background()))
+	assert.Len(t, requests, 1) // second page is not requested until needed
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+	assert.False(t, it.Next(context.Background()))
+
+	anonymous, err := NewAPIClient()
+	assert.NoError(t, err)
+	_, err = anonymous.ListSubmissions(context.Background(), SubmissionFilter{})
+	assert.ErrorIs(t, err, ErrorUnauthorized)
+}
+
+func TestUnit_GetSubmission(t *testing.T) {
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": func(w http.ResponseWriter, r *http.Request) {
+			q := graphQLRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&q))
+			if q.Variables[variableSubmissionID] != float64(3) {
+				writeJSON(t, w, `{"data":{"submissionDetails":null}}`)
+				return
+			}
+			writeJSON(t, w, `{"data":{"submissionDetails":{"runtimeDisplay":"4 ms","memoryDisplay":"4.2 MB",`+
+				`"code":"func twoSum() {}","timestamp":1690000003,"statusCode":10,`+
+				`"lang":{"name":"golang","verboseName":"Go"},"question":{"title":"Two Sum","titleSlug":"two-sum"}}}}`)
+		},
+	})
+
+	s, err := c.GetSubmission(context.Background(), "3")
+	assert.NoError(t, err)
+	assert.Equal(t, Submission{
+		ID:         "3",
+		Title:      "Two Sum",
+		TitleSlug:  "two-sum",
+		StatusCode: StatusCodeAccepted,
+		Status:     "Accepted",
+		Lang:       "golang",
+		LangName:   "Go",
+		Runtime:    "4 ms",
+		Memory:     "4.2 MB",
+		Timestamp:  time.Unix(1690000003, 0).UTC(),
+		Code:       "func twoSum() {}",
+	}, s)
+
+	_, err = c.GetSubmission(context.Background(), "4")
+	assert.ErrorIs(t, err, ErrorSubmissionNotFound)
+
+	_, err = c.GetSubmission(context.Background(), "abc")
+	assert.ErrorIs(t, err, ErrorSubmissionNotFound)
+}
@@ -0,0 +1,145 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	submitPathTemplate = "/problems/%s/submit/"
+
+	StatusCodeAccepted            = 10
+	StatusCodeWrongAnswer         = 11
+	StatusCodeMemoryLimitExceeded = 12
+	StatusCodeOutputLimitExceeded = 13
+	StatusCodeTimeLimitExceeded   = 14
+	StatusCodeRuntimeError        = 15
+	StatusCodeInternalError       = 16
+	StatusCodeCompileError        = 20
+)
+
+var statusMessages = map[int]string{
+	StatusCodeAccepted:            "Accepted",
+	StatusCodeWrongAnswer:         "Wrong Answer",
+	StatusCodeMemoryLimitExceeded: "Memory Limit Exceeded",
+	StatusCodeOutputLimitExceeded: "Output Limit Exceeded",
+	StatusCodeTimeLimitExceeded:   "Time Limit Exceeded",
+	StatusCodeRuntimeError:        "Runtime Error",
+	StatusCodeInternalError:       "Internal Error",
+	StatusCodeCompileError:        "Compile Error",
+}
+
+type (
+	// SubmissionResult is the final judge verdict for a submitted solution.
+	SubmissionResult struct {
+		SubmissionID string
+		StatusCode   int    // see StatusCode* constants
+		Status       string // human readable status, e.g. "Accepted"
+
+		Runtime           string // e.g. "4 ms"
+		Memory            string // e.g. "6.2 MB"
+		RuntimePercentile float64
+		MemoryPercentile  float64
+
+		TotalCorrect   int
+		TotalTestcases int
+
+		// set for the first failed test
+		FailedInput    string
+		ExpectedOutput string
+		ActualOutput   string
+		StdOutput      string
+
+		CompileError string
+		RuntimeError string
+	}
+
+	submitRequest struct {
+		Lang       string `json:"lang"`
+		QuestionID string `json:"question_id"`
+		TypedCode  string `json:"typed_code"`
+	}
+
+	submitResponse struct {
+		SubmissionID int64 `json:"submission_id"`
+	}
+)
+
+func (r *SubmissionResult) Accepted() bool {
+	return r.StatusCode == StatusCodeAccepted
+}
+
+// Submit sends the solution to the judge and waits for its verdict, the client must have a session.
+func (c *Client) Submit(ctx context.Context, titleSlug, langSlug, code string) (SubmissionResult, error) {
+	if err := c.requireSession(); err != nil {
+		return SubmissionResult{}, err
+	}
+
+	question, err := c.judgeQuestion(ctx, titleSlug)
+	if err != nil {
+		return SubmissionResult{}, wrapAPIError(err, "get question id")
+	}
+
+	referer := c.baseURL + fmt.Sprintf(problemRefererTemplate, titleSlug)
+	req, err := c.newRESTRequest(ctx, http.MethodPost, fmt.Sprintf(submitPathTemplate, titleSlug), submitRequest{
+		Lang:       langSlug,
+		QuestionID: question.QuestionData.QuestionID,
+		TypedCode:  code,
+	})
+	if err != nil {
+		return SubmissionResult{}, wrapAPIError(err, "init request")
+	}
+	c.addRefererHeader(req, referer)
+
+	body, err := c.doNonIdempotentRESTRequest(req)
+	if err != nil {
+		return SubmissionResult{}, wrapAPIError(err, "submit solution")
+	}
+
+	parsedResponse := &submitResponse{}
+	if err = json.Unmarshal(body, parsedResponse); err != nil || parsedResponse.SubmissionID == 0 {
+		return SubmissionResult{}, fmt.Errorf("%w: unexpected submit response: %s", ErrorSystem, body)
+	}
+	id := strconv.FormatInt(parsedResponse.SubmissionID, 10)
+
+	check, err := c.pollJudge(ctx, id, referer)
+	if err != nil {
+		return SubmissionResult{}, wrapAPIError(err, "wait for judge result")
+	}
+
+	return submissionResultFromCheck(id, check), nil
+}
+
+func submissionResultFromCheck(id string, check *checkResponse) SubmissionResult {
+	r := SubmissionResult{
+		SubmissionID:      id,
+		StatusCode:        check.StatusCode,
+		Status:            check.StatusMsg,
+		Runtime:           check.StatusRuntime,
+		Memory:            check.StatusMemory,
+		RuntimePercentile: check.RuntimePercentile,
+		MemoryPercentile:  check.MemoryPercentile,
+		TotalCorrect:      check.TotalCorrect,
+		TotalTestcases:    check.TotalTestcases,
+		CompileError:      check.FullCompileError,
+		RuntimeError:      check.FullRuntimeError,
+	}
+	if r.CompileError == "" {
+		r.CompileError = check.CompileError
+	}
+	if r.RuntimeError == "" {
+		r.RuntimeError = check.RuntimeError
+	}
+
+	if !r.Accepted() {
+		r.FailedInput = check.LastTestcase
+		r.ExpectedOutput = string(check.ExpectedOutput)
+		r.ActualOutput = string(check.CodeOutput)
+		r.StdOutput = string(check.StdOutput)
+	}
+
+	return r
+}
@@ -0,0 +1,190 @@
+package graphqlapiservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSession = Session{SessionID: "session_value", CSRFToken: "csrf_value"}
+
+// newStandInClient starts a local server with given handlers and returns a signed-in client pointed to it.
+func newStandInClient(t *testing.T, handlers map[string]http.HandlerFunc) *Client {
+	mux := http.NewServeMux()
+	for pattern, handler := range handlers {
+		mux.HandleFunc(pattern, handler)
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	c, err := NewAPIClient(
+		WithBaseURL(server.URL),
+		WithSession(testSession),
+		WithJudgePolling(time.Millisecond, time.Second),
+		WithRetryPolicy(RetryPolicy{}),
+		WithRateLimit(0, 0),
+		WithLogger(nil),
+	)
+	assert.NoError(t, err)
+	return c
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, err := w.Write([]byte(body))
+	assert.NoError(t, err)
+}
+
+func questionIDHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, `{"data":{"questionData":{"questionId":"1"}}}`)
+	}
+}
+
+func TestUnit_Submit(t *testing.T) {
+	var checks int32
+	verdict := `{"state":"SUCCESS","status_code":10,"status_msg":"Accepted","submission_id":"123",` +
+		`"status_runtime":"4 ms","status_memory":"4.2 MB","runtime_percentile":91.5,"memory_percentile":40.1,` +
+		`"total_correct":57,"total_testcases":57,"last_testcase":"","expected_output":"","code_output":""}`
+
+	c := newStandInClient(t, map[string]http.HandlerFunc{
+		"/graphql": questionIDHandler(t),
+		"/problems/two-sum/submit/": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, testSession.CSRFToken, r.Header.Get(csrfTokenHeader))
+			cookie, err := r.Cookie(sessionCookie)
+			assert.NoError(t, err)
+			assert.Equal(t, testSession.SessionID, cookie.Value)
+
+			payload := submitRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			assert.Equal(t, submitRequest{Lang: LangSlugGolang, QuestionID: "1", TypedCode: "func twoSum() {}"}, payload)
+
+			writeJSON(t, w, `{"submission_id":123}`)
+		},
+		"/submissions/detail/123/check/": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			if atomic.AddInt32(&checks, 1) < 3 {
+				writeJSON(t, w, `{"state":"PENDING"}`)
+				return
+			}
+			writeJSON(t, w, verdict)
+		},
+	})
+
+	r, err := c.Submit(context.Background(), "two-sum", LangSlugGolang, "func twoSum() {}")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&checks))
+	assert.True(t, r.Accepted())
+	assert.Equal(t, SubmissionResult{
+		SubmissionID:      "123",
+		StatusCode:        StatusCodeAccepted,
+		Status:            "Accepted",
+		Runtime:           "4 ms",
+		Memory:            "4.2 MB",
+		RuntimePercentile: 91.5,
+		MemoryPercentile:  40.1,
+		TotalCorrect:      57,
+		TotalTestcases:    57,
+	}, r)
+}
+
+func TestUnit_SubmitFailures(t *testing.T) {
+	submitHandler := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, `{"submission_id":123}`)
+	}
+
+	t.Run("wrong answer", func(t *testing.T) {
+		c := newStandInClient(t, map[string]http.HandlerFunc{
+			"/graphql":                  questionIDHandler(t),
+			"/problems/two-sum/submit/": submitHandler,
+			"/submissions/detail/123/check/": func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, `{"state":"SUCCESS","status_code":11,"status_msg":"Wrong Answer",`+
+					`"total_correct":3,"total_testcases":57,"last_testcase":"[2,7,11,15]\n9",`+
+					`"expected_output":"[0,1]","code_output":"[1,0]","std_output":"debug\n"}`)
+			},
+		})
+
+		r, err := c.Submit(context.Background(), "two-sum", LangSlugGolang, "")
+		assert.NoError(t, err)
+		assert.False(t, r.Accepted())
+		assert.Equal(t, "[2,7,11,15]\n9", r.FailedInput)
+		assert.Equal(t, "[0,1]", r.ExpectedOutput)
+		assert.Equal(t, "[1,0]", r.ActualOutput)
+		assert.Equal(t, "debug\n", r.StdOutput)
+	})
+
+	t.Run("compile error", func(t *testing.T) {
+		c := newStandInClient(t, map[string]http.HandlerFunc{
+			"/graphql":                  questionIDHandler(t),
+			"/problems/two-sum/submit/": submitHandler,
+			"/submissions/detail/123/check/": func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, `{"state":"SUCCESS","status_code":20,"status_msg":"Compile Error",`+
+					`"compile_error":"Line 1: syntax error","full_compile_error":"Line 1: syntax error\nsolution.go"}`)
+			},
+		})
+
+		r, err := c.Submit(context.Background(), "two-sum", LangSlugGolang, "")
+		assert.NoError(t, err)
+		assert.Equal(t, StatusCodeCompileError, r.StatusCode)
+		assert.Equal(t, "Line 1: syntax error\nsolution.go", r.CompileError)
+	})
+
+	t.Run("judge timeout", func(t *testing.T) {
+		c := newStandInClient(t, map[string]http.HandlerFunc{
+			"/graphql":                  questionIDHandler(t),
+			"/problems/two-sum/submit/": submitHandler,
+			"/submissions/detail/123/check/": func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, `{"state":"STARTED"}`)
+			},
+		})
+		c.pollTimeout = 20 * time.Millisecond
+
+		_, err := c.Submit(context.Background(), "two-sum", LangSlugGolang, "")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("unknown problem", func(t *testing.T) {
+		c := newStandInClient(t, map[string]http.HandlerFunc{
+			"/graphql": func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(t, w, `{"data":{"questionData":null}}`)
+			},
+		})
+
+		_, err := c.Submit(context.Background(), "unknown", LangSlugGolang, "")
+		assert.ErrorIs(t, err, ErrorProblemNotFound)
+	})
+
+	t.Run("server error is not retried", func(t *testing.T) {
+		var posts int32
+		c := newStandInClient(t, map[string]http.HandlerFunc{
+			"/graphql": questionIDHandler(t),
+			"/problems/two-sum/submit/": func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&posts, 1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				submitHandler(w, r)
+			},
+		})
+		c.retry = testRetryPolicy()
+
+		_, err := c.Submit(context.Background(), "two-sum", LangSlugGolang, "")
+		assert.ErrorIs(t, err, ErrorUnavailable)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&posts))
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		c, err := NewAPIClient()
+		assert.NoError(t, err)
+
+		_, err = c.Submit(context.Background(), "two-sum", LangSlugGolang, "")
+		assert.ErrorIs(t, err, ErrorUnauthorized)
+	})
+}
@@ -0,0 +1,260 @@
+package graphqlapiservice
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"leetcode-tools/pkg/graphql-api-service/internal/content"
+)
+
+const (
+	exampleInputLabel       = "Input:"
+	exampleOutputLabel      = "Output:"
+	exampleExplanationLabel = "Explanation:"
+	preIndent               = "    " // of code blocks in the text rendering
+)
+
+var (
+	exampleHeader = regexp.MustCompile(`^Example(\s+\d+)?\s*:$`)
+	// sections following the examples, recognized at the top level only
+	exampleSectionEnd = regexp.MustCompile(`^(Constraints|Follow[- ]?up|Note)\b`)
+	assignment        = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*=\s*`)
+
+	floatingPointTypes = map[string]bool{"double": true, "float": true, "double[]": true, "float[]": true}
+	// statements of floating-point problems promise to accept answers within e.g. 10^-5 of the actual one
+	floatingPointHint = regexp.MustCompile(`within 10\^\(?-\d+\)? of the (actual|correct|exact)`)
+	// a sentence about the returned value, e.g. "You can return the answer in any order", rather than
+	// about operations or moves that may be done in any order
+	anyOrderHint = regexp.MustCompile(`\b(return|returned|returns|answer|output|result)\b[^.\n]*\bin any order`)
+)
+
+type (
+	// TestCase is an example of the problem statement.
+	TestCase struct {
+		Input       map[string]string // parameter name => raw value, as in Problem.ExampleTestcases
+		Output      string            // raw expected value
+		Explanation string
+
+		// the flags are the same for all test cases of a problem
+		AnyOrder      bool // elements of the output may come in any order
+		FloatingPoint bool // the output is compared with a tolerance
+	}
+
+	example struct {
+		input       string
+		output      string
+		explanation []string
+	}
+)
+
+// TestCases parses the examples of the statement, mapping their inputs to MetaData.InputParameters.
+// Input values are taken from ExampleTestcases when the problem has them, they must match the statement.
+// Problems whose examples don't list named parameters, e.g. design problems, are not supported.
+func (p *Problem) TestCases() ([]TestCase, error) {
+	text := content.Text(p.Content, 0)
+	examples, err := parseExamples(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples in statement")
+	}
+
+	names := make([]string, 0, len(p.MetaData.InputParameters))
+	for _, param := range p.MetaData.InputParameters {
+		names = append(names, param.Name)
+	}
+
+	lowerText := strings.ToLower(text)
+	anyOrder := anyOrderHint.MatchString(lowerText)
+	floatingPoint := floatingPointTypes[p.MetaData.ReturnParameter.Type] || floatingPointHint.MatchString(lowerText)
+
+	cases := make([]TestCase, 0, len(examples))
+	for i, e := range examples {
+		input, order, err := parseInput(e.input)
+		if err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+		if len(names) == 0 {
+			// no metadata, parameters are named by the first example
+			names = order
+		}
+		if err = checkParameters(names, input); err != nil {
+			return nil, fmt.Errorf("example %d: %w", i+1, err)
+		}
+
+		cases = append(cases, TestCase{
+			Input:         input,
+			Output:        e.output,
+			Explanation:   strings.TrimSpace(strings.Join(e.explanation, "\n")),
+			AnyOrder:      anyOrder,
+			FloatingPoint: floatingPoint,
+		})
+	}
+
+	if err = crossCheckTestcases(cases, names, p.ExampleTestcases); err != nil {
+		return nil, err
+	}
+
+	return cases, nil
+}
+
+// parseExamples collects the input, output and explanation of every example of the text rendering of a statement.
+func parseExamples(text string) ([]example, error) {
+	var examples []example
+	var current *example
+	explaining := false
+
+	for _, line := range strings.Split(text, "\n") {
+		topLevel := line != "" && line[0] != ' '
+		trimmed := strings.TrimSpace(line)
+
+		if topLevel && exampleHeader.MatchString(trimmed) {
+			examples = append(examples, example{})
+			current = &examples[len(examples)-1]
+			explaining = false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if topLevel && exampleSectionEnd.MatchString(trimmed) {
+			current = nil
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, exampleInputLabel):
+			current.input = strings.TrimSpace(strings.TrimPrefix(trimmed, exampleInputLabel))
+			explaining = false
+		case strings.HasPrefix(trimmed, exampleOutputLabel):
+			current.output = strings.TrimSpace(strings.TrimPrefix(trimmed, exampleOutputLabel))
+			explaining = false
+		case strings.HasPrefix(trimmed, exampleExplanationLabel):
+			current.explanation = append(current.explanation, strings.TrimSpace(strings.TrimPrefix(trimmed, exampleExplanationLabel)))
+			explaining = true
+		case explaining:
+			current.explanation = append(current.explanation, strings.TrimRight(strings.TrimPrefix(line, preIndent), " "))
+		}
+	}
+
+	for i, e := range examples {
+		if e.input == "" || e.output == "" {
+			return nil, fmt.Errorf("example %d: no input or output", i+1)
+		}
+	}
+	return examples, nil
+}
+
+// parseInput splits "nums = [2,7,11,15], target = 9" into values by name, also returning the names in order.
+func parseInput(input string) (map[string]string, []string, error) {
+	values := make(map[string]string)
+	var names []string
+
+	for _, part := range splitTopLevel(input) {
+		m := assignment.FindStringSubmatch(part)
+		if m == nil {
+			if len(names) == 0 {
+				return nil, nil, fmt.Errorf("input %q is not a list of parameters", input)
+			}
+			// a comma within a value that isn't quoted nor bracketed
+			last := names[len(names)-1]
+			values[last] += "," + part
+			continue
+		}
+
+		name := m[1]
+		if _, ok := values[name]; ok {
+			return nil, nil, fmt.Errorf("parameter %s is repeated", name)
+		}
+		values[name] = strings.TrimSpace(part[len(m[0]):])
+		names = append(names, name)
+	}
+
+	return values, names, nil
+}
+
+// splitTopLevel splits s at commas that are neither quoted nor bracketed.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth := 0
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func checkParameters(names []string, input map[string]string) error {
+	if len(names) != len(input) {
+		return fmt.Errorf("%d parameters, expected %d", len(input), len(names))
+	}
+	for _, name := range names {
+		if _, ok := input[name]; !ok {
+			return fmt.Errorf("no value of parameter %s", name)
+		}
+	}
+	return nil
+}
+
+// crossCheckTestcases replaces input values with the matching lines of exampleTestcases, which list
+// every parameter of every example on its own line.
+func crossCheckTestcases(cases []TestCase, names []string, exampleTestcases string) error {
+	exampleTestcases = strings.TrimSpace(exampleTestcases)
+	if exampleTestcases == "" {
+		return nil
+	}
+
+	lines := strings.Split(exampleTestcases, "\n")
+	if len(lines) != len(cases)*len(names) {
+		return fmt.Errorf("%d example test case lines, expected %d for %d examples", len(lines), len(cases)*len(names), len(cases))
+	}
+
+	for i := range cases {
+		for j, name := range names {
+			raw := strings.TrimRight(lines[i*len(names)+j], "\r")
+			if compactValue(raw) != compactValue(cases[i].Input[name]) {
+				return fmt.Errorf("example %d: parameter %s is %s in the statement and %s in example test cases",
+					i+1, name, cases[i].Input[name], raw)
+			}
+			cases[i].Input[name] = raw
+		}
+	}
+	return nil
+}
+
+// compactValue drops whitespace outside of strings, statements tend to add spaces after commas.
+func compactValue(s string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(s):
+			b.WriteByte(c)
+			i++
+			c = s[i]
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t'):
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
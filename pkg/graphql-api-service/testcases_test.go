@@ -0,0 +1,160 @@
+package graphqlapiservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readStatement(t *testing.T, titleSlug string) string {
+	data, err := os.ReadFile(filepath.Join("internal", "content", "testdata", titleSlug+".html")) //nolint:gosec // test fixtures
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestUnit_TestCases(t *testing.T) {
+	params := func(names ...string) MetaData {
+		md := MetaData{}
+		for _, name := range names {
+			md.InputParameters = append(md.InputParameters, Parameter{Name: name})
+		}
+		return md
+	}
+
+	testCases := map[string]struct {
+		problem  Problem
+		expected []TestCase
+		err      string
+	}{
+		"any order": {
+			problem: Problem{
+				Content:          readStatement(t, "two-sum"),
+				MetaData:         params("nums", "target"),
+				ExampleTestcases: "[2,7,11,15]\n9\n[3,2,4]\n6\n[3,3]\n6",
+			},
+			expected: []TestCase{
+				{
+					Input:       map[string]string{"nums": "[2,7,11,15]", "target": "9"},
+					Output:      "[0,1]",
+					Explanation: "Because nums[0] + nums[1] == 9, we return [0, 1].",
+					AnyOrder:    true,
+				},
+				{Input: map[string]string{"nums": "[3,2,4]", "target": "6"}, Output: "[1,2]", AnyOrder: true},
+				{Input: map[string]string{"nums": "[3,3]", "target": "6"}, Output: "[0,1]", AnyOrder: true},
+			},
+		},
+		"operations in any order": {
+			problem: Problem{
+				Content: `<p>You may perform the operations in any order. Return the minimum number of operations.</p>` +
+					`<p><strong>Example 1:</strong></p><pre><strong>Input:</strong> nums = [3,1,2]
+<strong>Output:</strong> 2</pre>`,
+				MetaData: params("nums"),
+			},
+			expected: []TestCase{{Input: map[string]string{"nums": "[3,1,2]"}, Output: "2"}},
+		},
+		"floating point": {
+			problem: Problem{
+				Content: readStatement(t, "powx-n"),
+				MetaData: MetaData{
+					InputParameters: []Parameter{{Name: "x", Type: "double"}, {Name: "n", Type: "integer"}},
+					ReturnParameter: Parameter{Type: "double"},
+				},
+				ExampleTestcases: "2.00000\n10\n2.10000\n3\n2.00000\n-2",
+			},
+			expected: []TestCase{
+				{Input: map[string]string{"x": "2.00000", "n": "10"}, Output: "1024.00000", FloatingPoint: true},
+				{Input: map[string]string{"x": "2.10000", "n": "3"}, Output: "9.26100", FloatingPoint: true},
+				{
+					Input:         map[string]string{"x": "2.00000", "n": "-2"},
+					Output:        "0.25000",
+					Explanation:   "2^-2 = 1/2^2 = 1/4 = 0.25",
+					FloatingPoint: true,
+				},
+			},
+		},
+		"example blocks with multiline explanations": {
+			problem: Problem{
+				Content:          readStatement(t, "integer-to-roman"),
+				MetaData:         params("num"),
+				ExampleTestcases: "3749\n58",
+			},
+			expected: []TestCase{
+				{
+					Input:  map[string]string{"num": "3749"},
+					Output: `"MMMDCCXLIX"`,
+					Explanation: "3000 = MMM as 1000 (M) + 1000 (M) + 1000 (M)\n" +
+						" 700 = DCC as 500 (D) + 100 (C) + 100 (C)\n" +
+						"  40 = XL as 10 (X) less of 50 (L)\n" +
+						"   9 = IX as 1 (I) less of 10 (X)\n" +
+						"Note: 49 is not 1 (I) less of 50 (L) because the conversion is based on decimal places",
+				},
+				{Input: map[string]string{"num": "58"}, Output: `"LVIII"`, Explanation: "50 = L\n 8 = VIII"},
+			},
+		},
+		"parameters named by the statement": {
+			problem: Problem{
+				Content: `<p><strong>Example:</strong></p><pre><strong>Input:</strong> s = "a, b", pairs = [[0, 1], [1,2]]
+<strong>Output:</strong> "b, a"</pre><p><strong>Constraints:</strong></p>`,
+				ExampleTestcases: "\"a, b\"\n[[0,1],[1,2]]",
+			},
+			expected: []TestCase{
+				{Input: map[string]string{"s": `"a, b"`, "pairs": "[[0,1],[1,2]]"}, Output: `"b, a"`},
+			},
+		},
+		"no examples": {
+			problem: Problem{Content: "<p>Statement.</p>"},
+			err:     "no examples in statement",
+		},
+		"design problem": {
+			problem: Problem{
+				Content: `<p><strong>Example 1:</strong></p><pre><strong>Input</strong>
+["LRUCache", "put", "get"]
+[[2], [1, 1], [1]]
+<strong>Output:</strong> [null, null, 1]</pre>`,
+			},
+			err: "example 1: no input or output",
+		},
+		"parameters differ from metadata": {
+			problem: Problem{
+				Content:  readStatement(t, "reverse-linked-list"),
+				MetaData: params("list"),
+			},
+			err: "example 1: no value of parameter list",
+		},
+		"example test cases differ from statement": {
+			problem: Problem{
+				Content:          readStatement(t, "integer-to-roman"),
+				MetaData:         params("num"),
+				ExampleTestcases: "3749\n59",
+			},
+			err: "example 2: parameter num is 58 in the statement and 59 in example test cases",
+		},
+		"missing example test cases": {
+			problem: Problem{
+				Content:          readStatement(t, "integer-to-roman"),
+				MetaData:         params("num"),
+				ExampleTestcases: "3749",
+			},
+			err: "1 example test case lines, expected 2 for 2 examples",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cases, err := test.problem.TestCases()
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, cases)
+		})
+	}
+}
+
+func TestUnit_SplitTopLevel(t *testing.T) {
+	assert.Equal(t, []string{"a = [1,2]", ` b = "x,\"y"`, " c = {1,(2,3)}"}, splitTopLevel(`a = [1,2], b = "x,\"y", c = {1,(2,3)}`))
+	assert.Equal(t, `"a b"[1,2]`, compactValue(`"a b" [1, 2]`))
+}